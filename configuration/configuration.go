@@ -11,11 +11,263 @@ import (
 var configuration *Configuration
 
 type Configuration struct {
-	ServerConfig     config.AppConfig
-	MongoConfig      config.MongoConfig
-	ZohoConfig       ZohoConfig
-	AuthClientConfig HttpClientConfig
-	UIConfig         UIConfig
+	ServerConfig        config.AppConfig
+	MongoConfig         config.MongoConfig
+	ZohoConfig          ZohoConfig
+	AuthClientConfig    HttpClientConfig
+	UIConfig            UIConfig
+	MigrationConfig     MigrationConfig
+	ElasticConfig       ElasticConfig
+	AssetConfig         AssetConfig
+	AssetVerifierConfig AssetVerifierConfig
+	StorageConfig       StorageConfig
+	UploadConfig        UploadConfig
+	DeityConfig         DeityConfig
+	StitchingConfig     StitchingConfig
+	LoudnessConfig      LoudnessConfig
+	AlignmentConfig     AlignmentConfig
+	WaveformConfig      WaveformConfig
+	TranscodingConfig   TranscodingConfig
+	AuthConfig          AuthConfig
+	CorsConfig          CorsConfig
+	SnapshotConfig      SnapshotConfig
+	SchedulerConfig     SchedulerConfig
+	NotifierConfig      NotifierConfig
+	ChangeEventConfig   ChangeEventConfig
+	CdnConfig           CdnConfig
+}
+
+// AuthConfig lists the API keys and the JWT issuer trusted by the service. Keys are stored as
+// hex SHA-256 hashes. JWT signing keys come from JwksUrl, or from JwksFile for local keys;
+// the caller's role is read from RolesClaim, a string or a list of which the highest wins.
+type AuthConfig struct {
+	ApiKeys    []ApiKeyConfig
+	JwksUrl    string
+	JwksFile   string
+	JwksTTL    time.Duration
+	Issuer     string
+	Audience   string
+	RolesClaim string
+}
+
+type ApiKeyConfig struct {
+	Name    string
+	KeyHash string
+	Role    string
+}
+
+// CorsConfig.AllowedOrigins are the browser origins allowed to call the API, e.g. the host
+// serving the ingestion page.
+type CorsConfig struct {
+	AllowedOrigins []string
+}
+
+// SnapshotConfig.Enabled stores the records of every worksheet fetched from Zoho, so an
+// ingestion can be re-run against exactly what it read.
+type SnapshotConfig struct {
+	Enabled bool
+}
+
+// SchedulerConfig runs incremental ingestions in process. Cron is a standard five field
+// expression evaluated in Timezone; ContentTypes are ingested in the order given over the ID
+// range. Only the instance holding a job's lease runs it, renewing it every LeaseTTL / 3.
+type SchedulerConfig struct {
+	Enabled  bool
+	Timezone string
+	LeaseTTL time.Duration
+	Jobs     []ScheduledJobConfig
+}
+
+// ChangeEventConfig publishes content change events. Writes add them to the change outbox,
+// and the instance holding the relay lease publishes them in order to Backend: "kafka"
+// (Brokers), "nats" (NatsUrl), "sns" (Region, Endpoint for localstack) or "memory", which
+// keeps them in process for local use. Topic is the Kafka topic, the NATS subject prefix or
+// the SNS topic ARN. A failed publish is retried after PollInterval, doubling up to MaxBackoff.
+type ChangeEventConfig struct {
+	Enabled      bool
+	Backend      string
+	Topic        string
+	Brokers      []string
+	NatsUrl      string
+	Region       string
+	Endpoint     string
+	PollInterval time.Duration
+	BatchSize    int
+	MaxBackoff   time.Duration
+	LeaseTTL     time.Duration
+}
+
+// CdnConfig invalidates the CDN copies of assets that uploads and ingestions overwrite. Client
+// is "cloudfront", for DistributionId, or "fake", which logs invalidations and completes them
+// at once for local use. Paths go out BatchSize per invalidation; a directory with more than
+// WildcardThreshold changed assets is invalidated as "<dir>/*".
+type CdnConfig struct {
+	Enabled           bool
+	Client            string
+	DistributionId    string
+	BatchSize         int
+	WildcardThreshold int
+}
+
+// NotifierConfig routes ingestion notifications to sinks. Rules are keyed by content type,
+// and the "default" rule covers content types without one and requests that ingest none.
+type NotifierConfig struct {
+	Timeout time.Duration
+	Sinks   []NotifierSinkConfig
+	Rules   map[string]NotificationRuleConfig
+}
+
+// NotifierSinkConfig is one destination. Type is "log"; "webhook", which posts the
+// notification as JSON signed with Secret; "slack", an incoming webhook Url; or "email", sent
+// through the SMTP server to To.
+type NotifierSinkConfig struct {
+	Name     string
+	Type     string
+	Url      string
+	Secret   string
+	SmtpHost string
+	SmtpPort int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// NotificationRuleConfig picks the events sent for a content type and the sinks, by name,
+// they go to; all sinks when Sinks is empty. A run with at least WarningThreshold validation
+// warnings for the content type sends validation_warnings; 0 never does.
+type NotificationRuleConfig struct {
+	OnSuccess        bool
+	OnFailure        bool
+	WarningThreshold int
+	Sinks            []string
+}
+
+type ScheduledJobConfig struct {
+	Name         string
+	Cron         string
+	ContentTypes []string
+	StartID      int
+	EndID        int
+}
+
+// TranscodingConfig describes the bitrate ladder produced from stotra and prarthana audio with
+// ffmpeg. Codec is "aac" or "mp3". DownloadTimeout bounds fetching uploaded prarthana audio
+// from the CDN to transcode it.
+type TranscodingConfig struct {
+	Enabled           bool
+	FfmpegPath        string
+	HlsSegmentSeconds int
+	Renditions        []RenditionConfig
+	DownloadTimeout   time.Duration
+}
+
+type RenditionConfig struct {
+	Codec       string
+	BitrateKbps int
+}
+
+// WaveformConfig.Points is the number of min/max pairs a waveform is reduced to.
+type WaveformConfig struct {
+	Enabled bool
+	Points  int
+}
+
+// AlignmentConfig tunes the pause detection used to place shlok boundaries when the sheet
+// has no timestamps for a stotra.
+type AlignmentConfig struct {
+	SilenceThresholdDbfs float64
+	MinPauseMs           int
+}
+
+// LoudnessConfig sets the limits stotra audio analysis flags: integrated loudness further
+// than ToleranceLu from TargetLufs, a peak above MaxPeakDbfs, leading or trailing silence
+// longer than MaxSilenceMs and more than MaxClippedSamples clipped samples.
+type LoudnessConfig struct {
+	TargetLufs        float64
+	ToleranceLu       float64
+	MaxPeakDbfs       float64
+	MaxSilenceMs      int
+	MaxClippedSamples int
+}
+
+// StitchingConfig controls generation of prarthana audio from stotra audio. GapMs of silence
+// separates stotras within a chapter and ChapterGapMs separates chapters; CrossfadeMs overlaps
+// consecutive stotras instead when their gap is zero.
+type StitchingConfig struct {
+	Enabled      bool
+	SampleRate   int
+	Channels     int
+	GapMs        int
+	ChapterGapMs int
+	CrossfadeMs  int
+}
+
+// DeityConfig.RequireDodImage fails a deity ingestion whose DOD flag is set but whose DOD
+// image is missing; otherwise the deity is ingested without one and a warning is logged.
+type DeityConfig struct {
+	RequireDodImage bool
+}
+
+type StorageConfig struct {
+	Backend         string
+	Bucket          string
+	Region          string
+	Endpoint        string
+	AccessKeyId     string
+	SecretAccessKey string
+	UsePathStyle    bool
+	LocalRoot       string
+}
+
+type UploadConfig struct {
+	MaxImageSize int64
+	MaxAudioSize int64
+}
+
+type AssetVerifierConfig struct {
+	Concurrency int
+	Timeout     time.Duration
+	CacheTTL    time.Duration
+	ImageSpecs  map[string]ImageSpecConfig
+}
+
+// ImageSpecConfig constrains the images of one asset kind. Zero values are not checked.
+// Transparency is "required", "forbidden" or empty for either.
+type ImageSpecConfig struct {
+	Format          string
+	MinWidth        int
+	MinHeight       int
+	MaxWidth        int
+	MaxHeight       int
+	AspectRatio     float64
+	AspectTolerance float64
+	MaxBytes        int64
+	Transparency    string
+}
+
+type AssetConfig struct {
+	BaseUrl      string
+	Templates    map[string]string
+	Environments map[string]AssetEnvironmentConfig
+}
+
+type AssetEnvironmentConfig struct {
+	BaseUrl   string
+	Templates map[string]string
+}
+
+type ElasticConfig struct {
+	Enabled     bool
+	Address     string
+	Username    string
+	Password    string
+	IndexPrefix string
+	Timeout     time.Duration
+}
+
+type MigrationConfig struct {
+	RunOnStartup bool
 }
 
 type UIConfig struct {
@@ -0,0 +1,50 @@
+package ingestion
+
+import (
+	"errors"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (con *Controller) UploadAssets(c *gin.Context) {
+	ctx := c.Request.Context()
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid multipart payload",
+		})
+		return
+	}
+	if trail := util.GetAuditTrailFromContext(ctx); trail != nil {
+		for _, file := range form.File["files"] {
+			trail.AddFile(file.Filename)
+		}
+	}
+	assets, err := con.service.AssetUploadService().Upload(ctx, c.Param("kind"), form.File["files"])
+	if err != nil {
+		_ = c.Error(err)
+		var invalidUploadErr *entity.InvalidUploadError
+		if errors.As(err, &invalidUploadErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": invalidUploadErr.Error(),
+				"data":    invalidUploadErr.Problems,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  http.StatusInternalServerError,
+			"message": "Error processing request: " + err.Error(),
+			"data":    assets,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Successful",
+		"data":    assets,
+	})
+}
@@ -0,0 +1,32 @@
+package ingestion
+
+import (
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (con *Controller) ListIngestionAudits(c *gin.Context) {
+	ctx := c.Request.Context()
+	var query entity.IngestionAuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid query parameters",
+		})
+		return
+	}
+	page, err := con.service.IngestionAuditService().List(ctx, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  http.StatusInternalServerError,
+			"message": "Error processing request: " + err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Successful",
+		"data":    page,
+	})
+}
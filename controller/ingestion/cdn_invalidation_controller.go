@@ -0,0 +1,95 @@
+package ingestion
+
+import (
+	"errors"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ListCdnInvalidations reports the invalidated paths, filtered by run, status or path.
+func (con *Controller) ListCdnInvalidations(c *gin.Context) {
+	ctx := c.Request.Context()
+	var query entity.CdnInvalidationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid query parameters",
+		})
+		return
+	}
+	page, err := con.service.CdnInvalidationService().List(ctx, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  http.StatusInternalServerError,
+			"message": "Error processing request: " + err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Successful",
+		"data":    page,
+	})
+}
+
+// GetCdnInvalidation returns one invalidation with its status refreshed from the CDN.
+func (con *Controller) GetCdnInvalidation(c *gin.Context) {
+	ctx := c.Request.Context()
+	invalidation, err := con.service.CdnInvalidationService().Get(ctx, c.Param("id"))
+	if errors.Is(err, entity.ErrInvalidationNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  http.StatusNotFound,
+			"message": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  http.StatusInternalServerError,
+			"message": "Error processing request: " + err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Successful",
+		"data":    invalidation,
+	})
+}
+
+// CreateCdnInvalidation invalidates paths by hand, for assets replaced outside an ingestion.
+func (con *Controller) CreateCdnInvalidation(c *gin.Context) {
+	ctx := c.Request.Context()
+	var request entity.CdnInvalidationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request payload",
+		})
+		return
+	}
+	invalidations, err := con.service.CdnInvalidationService().Invalidate(ctx, request.Paths)
+	if err != nil {
+		// kept on the context for the audit log
+		_ = c.Error(err)
+		if errors.Is(err, entity.ErrInvalidCdnPath) || errors.Is(err, entity.ErrCdnDisabled) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  http.StatusInternalServerError,
+			"message": "Error processing request: " + err.Error(),
+			"data":    invalidations,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Successful",
+		"data":    invalidations,
+	})
+}
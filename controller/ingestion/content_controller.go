@@ -0,0 +1,83 @@
+package ingestion
+
+import (
+	"errors"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (con *Controller) ListContent(contentType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var query entity.ContentQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Invalid query parameters",
+			})
+			return
+		}
+		page, err := con.service.ContentReadService().List(ctx, contentType, query)
+		if err != nil {
+			writeReadError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"status":  http.StatusOK,
+			"message": "Successful",
+			"data":    page,
+		})
+	}
+}
+
+func (con *Controller) GetContent(contentType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		document, err := con.service.ContentReadService().Get(ctx, contentType, c.Param("key"), c.Query("fields"))
+		if err != nil {
+			writeReadError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"status":  http.StatusOK,
+			"message": "Successful",
+			"data":    document,
+		})
+	}
+}
+
+func (con *Controller) ResolvePrarthana(c *gin.Context) {
+	ctx := c.Request.Context()
+	prarthana, err := con.service.ContentReadService().ResolvePrarthana(ctx, c.Param("key"), c.Query("language"))
+	if err != nil {
+		writeReadError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Successful",
+		"data":    prarthana,
+	})
+}
+
+func writeReadError(c *gin.Context, err error) {
+	var invalidQueryErr *entity.InvalidContentQueryError
+	switch {
+	case errors.As(err, &invalidQueryErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": invalidQueryErr.Error(),
+		})
+	case errors.Is(err, entity.ErrContentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  http.StatusNotFound,
+			"message": "Not found",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  http.StatusInternalServerError,
+			"message": "Error processing request: " + err.Error(),
+		})
+	}
+}
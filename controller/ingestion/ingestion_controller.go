@@ -2,6 +2,7 @@ package ingestion
 
 import (
 	"context"
+	"errors"
 	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
@@ -28,7 +29,6 @@ func InitIngestionController(ctx context.Context, service facade.Service, config
 
 func (con *Controller) ShlokIngestion(c *gin.Context) {
 	ctx := c.Request.Context()
-	ctx = util.SetZohoAccessTokenInContext(ctx, c.Request.Header.Get("zoho-access-token"))
 	var request entity.IngestionRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -37,24 +37,21 @@ func (con *Controller) ShlokIngestion(c *gin.Context) {
 		})
 		return
 	}
-	err := con.service.ShlokIngestionService().ShlokIngestion(ctx, request.StartID, request.EndID)
+	ctx = util.SetSheetSnapshotsInContext(ctx, request.Snapshots)
+	err := con.service.ShlokIngestionService().ShlokIngestion(ctx, request.StartID, request.EndID, request.Incremental)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  http.StatusInternalServerError,
-			"message": "Error processing request: " + err.Error(),
-		})
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"status":  http.StatusOK,
 		"message": "Successful",
-		"data":    nil,
+		"data":    ingestionReport(ctx),
 	})
 }
 
 func (con *Controller) StotraIngestion(c *gin.Context) {
 	ctx := c.Request.Context()
-	ctx = util.SetZohoAccessTokenInContext(ctx, c.Request.Header.Get("zoho-access-token"))
 	var request entity.IngestionRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -63,24 +60,21 @@ func (con *Controller) StotraIngestion(c *gin.Context) {
 		})
 		return
 	}
-	_, err := con.service.StotraIngestionService().StotraIngestion(ctx, request.StartID, request.EndID)
+	ctx = util.SetSheetSnapshotsInContext(ctx, request.Snapshots)
+	_, err := con.service.StotraIngestionService().StotraIngestion(ctx, request.StartID, request.EndID, request.Incremental)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  http.StatusInternalServerError,
-			"message": "Error processing request: " + err.Error(),
-		})
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"status":  http.StatusOK,
 		"message": "Successful",
-		"data":    nil,
+		"data":    ingestionReport(ctx),
 	})
 }
 
 func (con *Controller) PrarthanaIngestion(c *gin.Context) {
 	ctx := c.Request.Context()
-	ctx = util.SetZohoAccessTokenInContext(ctx, c.Request.Header.Get("zoho-access-token"))
 	var requestBody entity.IngestionRequest
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -89,24 +83,21 @@ func (con *Controller) PrarthanaIngestion(c *gin.Context) {
 		})
 		return
 	}
-	_, err := con.service.PrarthanaIngestionService().PrarthanaIngestion(ctx, requestBody.StartID, requestBody.EndID)
+	ctx = util.SetSheetSnapshotsInContext(ctx, requestBody.Snapshots)
+	_, err := con.service.PrarthanaIngestionService().PrarthanaIngestion(ctx, requestBody.StartID, requestBody.EndID, requestBody.Incremental)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  http.StatusInternalServerError,
-			"message": "Error processing request: " + err.Error(),
-		})
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"status":  http.StatusOK,
 		"message": "Successful",
-		"data":    nil,
+		"data":    ingestionReport(ctx),
 	})
 }
 
 func (con *Controller) DeityIngestion(c *gin.Context) {
 	ctx := c.Request.Context()
-	ctx = util.SetZohoAccessTokenInContext(ctx, c.Request.Header.Get("zoho-access-token"))
 	var requestBody entity.IngestionRequest
 
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
@@ -116,12 +107,24 @@ func (con *Controller) DeityIngestion(c *gin.Context) {
 		})
 		return
 	}
-	_, err := con.service.DeityIngestionService().DeityIngestion(ctx, requestBody.StartID, requestBody.EndID)
+	ctx = util.SetSheetSnapshotsInContext(ctx, requestBody.Snapshots)
+	_, err := con.service.DeityIngestionService().DeityIngestion(ctx, requestBody.StartID, requestBody.EndID, requestBody.Incremental)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  http.StatusInternalServerError,
-			"message": "Error processing request: " + err.Error(),
-		})
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Successful",
+		"data":    ingestionReport(ctx),
+	})
+}
+
+func (con *Controller) SearchReindex(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := con.service.SearchIndexingService().Reindex(ctx)
+	if err != nil {
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -130,3 +133,41 @@ func (con *Controller) DeityIngestion(c *gin.Context) {
 		"data":    nil,
 	})
 }
+
+// ingestionReport returns the counts and skipped rows the request's audit trail collected.
+func ingestionReport(ctx context.Context) *entity.IngestionReport {
+	trail := util.GetAuditTrailFromContext(ctx)
+	if trail == nil {
+		return nil
+	}
+	report := trail.Report()
+	return &report
+}
+
+// writeError reports a failed ingestion; missing assets are returned as a structured report
+// so the whole list can be handed over instead of just the first failure.
+func writeError(c *gin.Context, err error) {
+	// kept on the context for the audit log
+	_ = c.Error(err)
+	var invalidSnapshotErr *entity.InvalidSnapshotError
+	if errors.As(err, &invalidSnapshotErr) || errors.Is(err, entity.ErrSnapshotNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Error processing request: " + err.Error(),
+		})
+		return
+	}
+	var missingAssetsErr *entity.MissingAssetsError
+	if errors.As(err, &missingAssetsErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"status":  http.StatusUnprocessableEntity,
+			"message": "Error processing request: " + err.Error(),
+			"data":    missingAssetsErr.Report,
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"status":  http.StatusInternalServerError,
+		"message": "Error processing request: " + err.Error(),
+	})
+}
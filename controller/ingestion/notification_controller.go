@@ -0,0 +1,18 @@
+package ingestion
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// SendTestNotification sends a test notification to every configured sink and reports the
+// delivery to each.
+func (con *Controller) SendTestNotification(c *gin.Context) {
+	ctx := c.Request.Context()
+	deliveries := con.service.NotifierService().SendTest(ctx)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Successful",
+		"data":    deliveries,
+	})
+}
@@ -0,0 +1,91 @@
+package ingestion
+
+import (
+	"errors"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (con *Controller) ListSheetSnapshots(c *gin.Context) {
+	ctx := c.Request.Context()
+	var query entity.SheetSnapshotQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid query parameters",
+		})
+		return
+	}
+	page, err := con.service.SheetSnapshotService().List(ctx, query)
+	if err != nil {
+		writeSnapshotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Successful",
+		"data":    page,
+	})
+}
+
+// GetSheetSnapshot returns the snapshot with its records.
+func (con *Controller) GetSheetSnapshot(c *gin.Context) {
+	ctx := c.Request.Context()
+	snapshot, records, err := con.service.SheetSnapshotService().Load(ctx, c.Param("id"))
+	if err != nil {
+		writeSnapshotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Successful",
+		"data": gin.H{
+			"snapshot": snapshot,
+			"records":  records,
+		},
+	})
+}
+
+func (con *Controller) DiffSheetSnapshots(c *gin.Context) {
+	ctx := c.Request.Context()
+	from, to := c.Query("from"), c.Query("to")
+	if from == "" || to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "from and to snapshot ids are required",
+		})
+		return
+	}
+	diff, err := con.service.SheetSnapshotService().Diff(ctx, from, to)
+	if err != nil {
+		writeSnapshotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Successful",
+		"data":    diff,
+	})
+}
+
+func writeSnapshotError(c *gin.Context, err error) {
+	var invalidSnapshotErr *entity.InvalidSnapshotError
+	switch {
+	case errors.As(err, &invalidSnapshotErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": invalidSnapshotErr.Error(),
+		})
+	case errors.Is(err, entity.ErrSnapshotNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  http.StatusNotFound,
+			"message": "Not found",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  http.StatusInternalServerError,
+			"message": "Error processing request: " + err.Error(),
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+type AssetStatus string
+
+const (
+	AssetFound       AssetStatus = "found"
+	AssetMissing     AssetStatus = "missing"
+	AssetForbidden   AssetStatus = "forbidden"
+	AssetRedirected  AssetStatus = "redirected"
+	AssetUnreachable AssetStatus = "unreachable"
+	AssetInvalid     AssetStatus = "invalid"
+)
+
+// AssetCheck is one asset a sheet row needs. Candidates are tried in order and the
+// first one found is used, e.g. a .wav with an .mp3 fallback.
+type AssetCheck struct {
+	Row        int      `json:"row"`
+	Kind       string   `json:"kind"`
+	Candidates []string `json:"candidates"`
+}
+
+// AssetCheckResult is the resolved check. ETag and LastModified are the validators the CDN
+// answered with for the found URL, so a file replaced under the same name can be told apart.
+type AssetCheckResult struct {
+	AssetCheck
+	Status       AssetStatus `json:"status"`
+	Url          string      `json:"url"`
+	StatusCode   int         `json:"status_code,omitempty"`
+	Location     string      `json:"location,omitempty"`
+	Problems     []string    `json:"problems,omitempty"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+}
+
+type MissingAsset struct {
+	Kind             string      `json:"kind"`
+	ExpectedFileName string      `json:"expected_file_name"`
+	Url              string      `json:"url"`
+	Status           AssetStatus `json:"status"`
+	StatusCode       int         `json:"status_code,omitempty"`
+	Location         string      `json:"location,omitempty"`
+	Problems         []string    `json:"problems,omitempty"`
+}
+
+type MissingAssetsRow struct {
+	Row    int            `json:"row"`
+	Assets []MissingAsset `json:"assets"`
+}
+
+type MissingAssetsReport struct {
+	ContentType string             `json:"content_type"`
+	Total       int                `json:"total"`
+	Rows        []MissingAssetsRow `json:"rows"`
+}
+
+// MissingAssetsError is returned by an ingestion when any asset of the batch is missing or
+// fails its image spec, so the whole report reaches the caller instead of just the first failure.
+type MissingAssetsError struct {
+	Report MissingAssetsReport
+}
+
+func (e *MissingAssetsError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d missing or invalid %s assets in %d rows", e.Report.Total, e.Report.ContentType, len(e.Report.Rows)))
+	for _, row := range e.Report.Rows {
+		for _, asset := range row.Assets {
+			sb.WriteString(fmt.Sprintf("\nrow %d: %s %s (%s)", row.Row, asset.Kind, asset.ExpectedFileName, asset.Status))
+			if len(asset.Problems) > 0 {
+				sb.WriteString(": " + strings.Join(asset.Problems, "; "))
+			}
+		}
+	}
+	return sb.String()
+}
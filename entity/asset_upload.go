@@ -0,0 +1,20 @@
+package entity
+
+import "strings"
+
+type UploadedAsset struct {
+	FileName string `json:"file_name"`
+	Name     string `json:"name"`
+	Key      string `json:"key"`
+	Url      string `json:"url"`
+}
+
+// InvalidUploadError lists every file of an upload that failed validation; nothing is
+// uploaded when it is returned.
+type InvalidUploadError struct {
+	Problems []string
+}
+
+func (e *InvalidUploadError) Error() string {
+	return strings.Join(e.Problems, "\n")
+}
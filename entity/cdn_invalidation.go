@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// InvalidationPending is stored before the batch is submitted to the CDN
+	InvalidationPending    = "pending"
+	InvalidationInProgress = "in_progress"
+	InvalidationCompleted  = "completed"
+	InvalidationFailed     = "failed"
+)
+
+var (
+	ErrInvalidationNotFound = errors.New("invalidation not found")
+	ErrCdnDisabled          = errors.New("cdn invalidation is disabled")
+	ErrInvalidCdnPath       = errors.New("invalid cdn path")
+)
+
+// CdnInvalidation is one batch of paths submitted to the CDN. Paths start with "/"; one
+// ending in "/*" covers every asset under it. RunId is the ingestion run that overwrote the
+// assets, or the request that asked for the invalidation.
+type CdnInvalidation struct {
+	Id          string     `json:"id" bson:"_id"`
+	CdnId       string     `json:"cdn_id,omitempty" bson:"cdn_id,omitempty"`
+	RunId       string     `json:"run_id,omitempty" bson:"run_id,omitempty"`
+	Paths       []string   `json:"paths" bson:"paths"`
+	Status      string     `json:"status" bson:"status"`
+	Error       string     `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" bson:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+// CdnInvalidationQuery filters invalidations; Path matches batches containing exactly that
+// path.
+type CdnInvalidationQuery struct {
+	Status   string `form:"status"`
+	RunId    string `form:"run_id"`
+	Path     string `form:"path"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type CdnInvalidationPage struct {
+	Items    []CdnInvalidation `json:"items"`
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+}
+
+// CdnInvalidationRequest invalidates paths by hand, for assets replaced outside the ingestion.
+type CdnInvalidationRequest struct {
+	Paths []string `json:"paths" binding:"required,min=1"`
+}
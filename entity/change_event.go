@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+const (
+	ChangeEventPending   = "pending"
+	ChangeEventPublished = "published"
+)
+
+// ChangeEvent tells downstream services a content document changed. Version increases with
+// every change of the document, so consumers can drop events older than the one they have
+// seen; ChangedFields are the top level stored fields that changed.
+type ChangeEvent struct {
+	Id            string    `json:"id" bson:"_id"`
+	ContentType   string    `json:"content_type" bson:"content_type"`
+	DocumentId    string    `json:"document_id" bson:"document_id"`
+	Version       int64     `json:"version" bson:"version"`
+	Action        string    `json:"action" bson:"action"`
+	ChangedFields []string  `json:"changed_fields,omitempty" bson:"changed_fields,omitempty"`
+	RunId         string    `json:"run_id,omitempty" bson:"run_id,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at" bson:"occurred_at"`
+}
+
+// OutboxEntry is a change event waiting in, or published from, the change outbox.
+type OutboxEntry struct {
+	Event         ChangeEvent `json:"event" bson:",inline"`
+	Status        string      `json:"status" bson:"status"`
+	Attempts      int         `json:"attempts" bson:"attempts"`
+	LastError     string      `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	NextAttemptAt time.Time   `json:"next_attempt_at" bson:"next_attempt_at"`
+	PublishedAt   *time.Time  `json:"published_at,omitempty" bson:"published_at,omitempty"`
+}
+
+type OutboxQuery struct {
+	Status      string `form:"status"`
+	ContentType string `form:"content_type"`
+	DocumentId  string `form:"document_id"`
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	PageSize    int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type OutboxPage struct {
+	Items    []OutboxEntry `json:"items"`
+	Total    int64         `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+}
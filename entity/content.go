@@ -0,0 +1,45 @@
+package entity
+
+import "errors"
+
+const (
+	ContentShloks     = "shloks"
+	ContentStotras    = "stotras"
+	ContentPrarthanas = "prarthanas"
+	ContentDeities    = "deities"
+)
+
+var ErrContentNotFound = errors.New("content not found")
+
+// ContentQuery filters and pages a content listing. Filters that do not apply to the
+// content type are rejected; Fields is a comma separated list of stored field paths.
+type ContentQuery struct {
+	Language       string `form:"language"`
+	Festival       string `form:"festival"`
+	Region         string `form:"region"`
+	IntentBased    *bool  `form:"intent_based"`
+	StudioRecorded *bool  `form:"studio_recorded"`
+	Fields         string `form:"fields"`
+	Page           int    `form:"page" binding:"omitempty,min=1"`
+	PageSize       int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// ContentDocument is a stored document as read from Mongo, keyed by its stored field names
+// so it can be projected with the same paths.
+type ContentDocument map[string]interface{}
+
+type ContentPage struct {
+	Items    []ContentDocument `json:"items"`
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+}
+
+// InvalidContentQueryError is returned for filters or fields a content type does not support.
+type InvalidContentQueryError struct {
+	Message string
+}
+
+func (e *InvalidContentQueryError) Error() string {
+	return e.Message
+}
@@ -14,6 +14,7 @@ type DeityDocument struct {
 	FestivalIds    []string            `json:"festival_ids" bson:"festival_ids"`
 	Region         []string            `json:"region" bson:"region"`
 	AliasesV1      map[string][]string `json:"aliases_v1" bson:"aliases_v1"`
+	SourceHash     string              `json:"source_hash" bson:"source_hash"`
 }
 
 type DeityUIInfo struct {
@@ -0,0 +1,8 @@
+package entity
+
+// DeityPrarthanaMapping holds the "deity to prarthana mapping" sheet keyed by sheet
+// (tmp) ids, with both directions already in display order.
+type DeityPrarthanaMapping struct {
+	DeityToPrarthanas  map[string][]string
+	PrarthanaToDeities map[string][]string
+}
@@ -0,0 +1,98 @@
+package entity
+
+import "time"
+
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+
+	ChangeInserted  = "inserted"
+	ChangeUpdated   = "updated"
+	ChangeUnchanged = "unchanged"
+	// ChangeSkipped is a row an incremental ingestion did not process since its source is unchanged
+	ChangeSkipped = "skipped"
+)
+
+// IngestionAudit is one append-only record of an ingestion request.
+type IngestionAudit struct {
+	Id         string           `json:"id" bson:"_id"`
+	Actor      Principal        `json:"actor" bson:"actor"`
+	Endpoint   string           `json:"endpoint" bson:"endpoint"`
+	StartID    int              `json:"start_id,omitempty" bson:"start_id,omitempty"`
+	EndID      int              `json:"end_id,omitempty" bson:"end_id,omitempty"`
+	Source     AuditSource      `json:"source" bson:"source"`
+	StartedAt  time.Time        `json:"started_at" bson:"started_at"`
+	FinishedAt time.Time        `json:"finished_at" bson:"finished_at"`
+	Outcome    string           `json:"outcome" bson:"outcome"`
+	Status     int              `json:"status" bson:"status"`
+	Error      string           `json:"error,omitempty" bson:"error,omitempty"`
+	Counts     AuditCounts      `json:"counts" bson:"counts"`
+	Changes    []DocumentChange `json:"changes" bson:"changes"`
+	// ContentTypes are the content types the request ingests, if any
+	ContentTypes []string `json:"content_types,omitempty" bson:"content_types,omitempty"`
+	// Warnings keeps the first validation warnings of the run; WarningCounts counts all of
+	// them by content type
+	Warnings      []IngestionWarning `json:"warnings,omitempty" bson:"warnings,omitempty"`
+	WarningCounts map[string]int     `json:"warning_counts,omitempty" bson:"warning_counts,omitempty"`
+	// ChangedAssets are the storage keys of assets the request overwrote; Invalidations are
+	// the ids of the CDN invalidations submitted for them
+	ChangedAssets []string `json:"changed_assets,omitempty" bson:"changed_assets,omitempty"`
+	Invalidations []string `json:"invalidations,omitempty" bson:"invalidations,omitempty"`
+}
+
+// AuditSource is where the ingested data came from: the worksheets read from the Zoho sheet
+// with the snapshot of each, or the uploaded files.
+type AuditSource struct {
+	SheetId    string            `json:"sheet_id,omitempty" bson:"sheet_id,omitempty"`
+	Worksheets []string          `json:"worksheets,omitempty" bson:"worksheets,omitempty"`
+	Snapshots  map[string]string `json:"snapshots,omitempty" bson:"snapshots,omitempty"`
+	Files      []string          `json:"files,omitempty" bson:"files,omitempty"`
+}
+
+type AuditCounts struct {
+	Inserted  int `json:"inserted" bson:"inserted"`
+	Updated   int `json:"updated" bson:"updated"`
+	Unchanged int `json:"unchanged" bson:"unchanged"`
+	Skipped   int `json:"skipped" bson:"skipped"`
+	Warnings  int `json:"warnings" bson:"warnings"`
+}
+
+// IngestionWarning is a problem in the source data that did not stop the ingestion.
+type IngestionWarning struct {
+	ContentType string `json:"content_type" bson:"content_type"`
+	Message     string `json:"message" bson:"message"`
+}
+
+// DocumentChange summarizes the write of one document; Fields lists the top level fields
+// whose value changed.
+type DocumentChange struct {
+	Collection string   `json:"collection" bson:"collection"`
+	Id         string   `json:"id" bson:"id"`
+	Action     string   `json:"action" bson:"action"`
+	Fields     []string `json:"fields,omitempty" bson:"fields,omitempty"`
+}
+
+type IngestionAuditQuery struct {
+	Subject  string    `form:"subject"`
+	Endpoint string    `form:"endpoint"`
+	Outcome  string    `form:"outcome"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page     int       `form:"page" binding:"omitempty,min=1"`
+	PageSize int       `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type IngestionAuditPage struct {
+	Items    []IngestionAudit `json:"items"`
+	Total    int64            `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+}
+
+// IngestionReport is returned by the ingestion endpoints.
+type IngestionReport struct {
+	Counts  AuditCounts `json:"counts"`
+	Skipped []string    `json:"skipped"`
+	// ChangedAssets are the overwritten assets whose CDN copies are invalidated after the request
+	ChangedAssets []string `json:"changed_assets"`
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+const (
+	NotificationJobSucceeded       = "job_succeeded"
+	NotificationJobFailed          = "job_failed"
+	NotificationValidationWarnings = "validation_warnings"
+	NotificationTest               = "test"
+)
+
+// Notification tells operators about the outcome of an ingestion. Job is the endpoint of an
+// API request or the name of a scheduled job.
+type Notification struct {
+	Event        string       `json:"event"`
+	Job          string       `json:"job"`
+	ContentTypes []string     `json:"content_types,omitempty"`
+	RunId        string       `json:"run_id,omitempty"`
+	Actor        string       `json:"actor,omitempty"`
+	Message      string       `json:"message"`
+	Counts       *AuditCounts `json:"counts,omitempty"`
+	Warnings     []string     `json:"warnings,omitempty"`
+	OccurredAt   time.Time    `json:"occurred_at"`
+}
+
+// NotificationDelivery is the result of sending a notification to one sink.
+type NotificationDelivery struct {
+	Sink  string `json:"sink"`
+	Error string `json:"error,omitempty"`
+}
@@ -7,6 +7,9 @@ type Chapter struct {
 	Title         map[string]string `bson:"title" `
 	StotraIds     []string          `bson:"stotra_ids"`
 	DurationInSec int               `bson:"-"`
+	// StartMs and DurationMs locate the chapter in the stitched prarthana audio
+	StartMs    int `bson:"start_ms"`
+	DurationMs int `bson:"duration_ms"`
 }
 
 type Variant struct {
@@ -31,12 +34,26 @@ type Prarthana struct {
 	UiInfo             PrarthanaUIInfo     `bson:"ui_info"`
 	AvailableLanguages []KeyValue          `bson:"available_languages"`
 	IntentBased        bool                `bson:"intent_based"`
+	SearchKeywords     []string            `bson:"search_keywords"`
+	SourceHash         string              `bson:"source_hash"`
 }
 
 type AudioInfo struct {
 	IsAudioAvailable bool   `json:"is_audio_available" bson:"is_audio_available"`
 	AudioUrl         string `json:"audio_url" bson:"audio_url"`
 	IsStudioRecorded bool   `json:"is_studio_recorded" bson:"is_studio_recorded"`
+	WaveformUrl      string `json:"waveform_url" bson:"waveform_url"`
+	// HlsUrl is the master playlist over all renditions, for clients that pick by network quality
+	HlsUrl     string           `json:"hls_url" bson:"hls_url"`
+	Renditions []AudioRendition `json:"renditions" bson:"renditions"`
+}
+
+// AudioRendition is one rung of the bitrate ladder: a progressive file and its HLS playlist.
+type AudioRendition struct {
+	Codec       string `json:"codec" bson:"codec"`
+	BitrateKbps int    `json:"bitrate_kbps" bson:"bitrate_kbps"`
+	Url         string `json:"url" bson:"url"`
+	HlsUrl      string `json:"hls_url" bson:"hls_url"`
 }
 
 type KeyValue struct {
@@ -47,5 +64,5 @@ type KeyValue struct {
 type PrarthanaUIInfo struct {
 	AlbumArt        string `json:"album_art" bson:"album_art"`
 	DefaultImageUrl string `json:"default_image_url" bson:"default_image_url"`
-	TemplateNumber  string `json:"template_number"`
+	TemplateNumber  string `json:"template_number" bson:"template_number"`
 }
@@ -0,0 +1,32 @@
+package entity
+
+type Role string
+
+const (
+	RoleViewer    Role = "viewer"
+	RoleEditor    Role = "editor"
+	RolePublisher Role = "publisher"
+)
+
+var roleRanks = map[Role]int{
+	RoleViewer:    1,
+	RoleEditor:    2,
+	RolePublisher: 3,
+}
+
+// Allows reports whether the role grants at least the required one; roles are ordered
+// viewer < editor < publisher.
+func (r Role) Allows(required Role) bool {
+	return roleRanks[r] > 0 && roleRanks[r] >= roleRanks[required]
+}
+
+func (r Role) Valid() bool {
+	return roleRanks[r] > 0
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string `json:"subject" bson:"subject"`
+	Role    Role   `json:"role" bson:"role"`
+	Method  string `json:"method" bson:"method"`
+}
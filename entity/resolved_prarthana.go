@@ -0,0 +1,61 @@
+package entity
+
+// PrarthanaContent is a prarthana with every stotra its variants reference and every shlok
+// those stotras reference, as stored.
+type PrarthanaContent struct {
+	Prarthana Prarthana `bson:",inline"`
+	Stotras   []Stotra  `bson:"stotras"`
+	Shloks    []Shlok   `bson:"shloks"`
+}
+
+// ResolvedPrarthana is a prarthana expanded down to shlok text in one language, as the app
+// renders it. Problems lists the missing pieces found while expanding.
+type ResolvedPrarthana struct {
+	Id               string            `json:"id"`
+	TmpId            string            `json:"tmp_id"`
+	Language         string            `json:"language"`
+	Title            string            `json:"title"`
+	AudioUrl         string            `json:"audio_url"`
+	HlsUrl           string            `json:"hls_url,omitempty"`
+	IsStudioRecorded bool              `json:"is_studio_recorded"`
+	Variants         []ResolvedVariant `json:"variants"`
+	Problems         []string          `json:"problems"`
+}
+
+type ResolvedVariant struct {
+	IsDefault  bool              `json:"is_default"`
+	Duration   string            `json:"duration"`
+	DurationMs int               `json:"duration_ms"`
+	Chapters   []ResolvedChapter `json:"chapters"`
+}
+
+// ResolvedChapter has the offsets of the chapter in the stitched audio when it was stitched,
+// and otherwise a duration summed from its stotras.
+type ResolvedChapter struct {
+	Order      int              `json:"order"`
+	Title      string           `json:"title"`
+	StartMs    int              `json:"start_ms"`
+	DurationMs int              `json:"duration_ms"`
+	Stotras    []ResolvedStotra `json:"stotras"`
+}
+
+type ResolvedStotra struct {
+	Id         string          `json:"id"`
+	Title      string          `json:"title"`
+	AudioUrl   string          `json:"audio_url"`
+	HlsUrl     string          `json:"hls_url,omitempty"`
+	DurationMs int             `json:"duration_ms"`
+	Missing    bool            `json:"missing,omitempty"`
+	Shloks     []ResolvedShlok `json:"shloks"`
+}
+
+// ResolvedShlok carries the shlok's time range in the stotra audio when timings are known.
+type ResolvedShlok struct {
+	Id          string `json:"id"`
+	Title       string `json:"title,omitempty"`
+	Text        string `json:"text"`
+	Explanation string `json:"explanation"`
+	StartMs     int    `json:"start_ms,omitempty"`
+	EndMs       int    `json:"end_ms,omitempty"`
+	Missing     bool   `json:"missing,omitempty"`
+}
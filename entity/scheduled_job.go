@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// JobLease gives one instance the right to run a scheduled job until ExpiresAt. The holder
+// renews it while the job runs; a lease left by a crashed instance lapses on its own.
+type JobLease struct {
+	Name       string    `json:"name" bson:"_id"`
+	Holder     string    `json:"holder" bson:"holder"`
+	AcquiredAt time.Time `json:"acquired_at" bson:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at" bson:"expires_at"`
+}
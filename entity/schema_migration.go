@@ -0,0 +1,9 @@
+package entity
+
+import "time"
+
+type SchemaMigration struct {
+	Version     int       `json:"version" bson:"_id"`
+	Description string    `json:"description" bson:"description"`
+	AppliedAt   time.Time `json:"applied_at" bson:"applied_at"`
+}
@@ -3,6 +3,10 @@ package entity
 type IngestionRequest struct {
 	StartID int `json:"start_id" binding:"required,min=1"`
 	EndID   int `json:"end_id" binding:"required,max=100000"`
+	// Snapshots pins worksheets, by name, to stored snapshots instead of live Zoho data
+	Snapshots map[string]string `json:"snapshots"`
+	// Incremental skips rows whose source hash matches the stored document
+	Incremental bool `json:"incremental"`
 }
 
 type ShlokaSheetResponse struct {
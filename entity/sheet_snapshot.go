@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrSnapshotNotFound = errors.New("sheet snapshot not found")
+
+// SheetSnapshot is the raw records of one worksheet as fetched from Zoho. Data is the
+// gzipped JSON array of records and Checksum the SHA-256 hex of the uncompressed array.
+type SheetSnapshot struct {
+	Id          string    `json:"id" bson:"_id"`
+	SheetId     string    `json:"sheet_id" bson:"sheet_id"`
+	Worksheet   string    `json:"worksheet" bson:"worksheet"`
+	Checksum    string    `json:"checksum" bson:"checksum"`
+	FetchedAt   time.Time `json:"fetched_at" bson:"fetched_at"`
+	RecordCount int       `json:"record_count" bson:"record_count"`
+	Size        int       `json:"size" bson:"size"`
+	Data        []byte    `json:"-" bson:"data,omitempty"`
+}
+
+type SheetSnapshotQuery struct {
+	Worksheet string `form:"worksheet"`
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	PageSize  int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type SheetSnapshotPage struct {
+	Items    []SheetSnapshot `json:"items"`
+	Total    int64           `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+}
+
+// SnapshotDiff compares the records of two snapshots of a worksheet. Records are matched
+// by their ID column, or by row index in worksheets without one.
+type SnapshotDiff struct {
+	Worksheet string         `json:"worksheet"`
+	From      string         `json:"from"`
+	To        string         `json:"to"`
+	Added     []string       `json:"added"`
+	Removed   []string       `json:"removed"`
+	Changed   []RecordChange `json:"changed"`
+}
+
+type RecordChange struct {
+	Key     string   `json:"key"`
+	Columns []string `json:"columns"`
+}
+
+// InvalidSnapshotError is returned when snapshots cannot be used for a request, e.g. a
+// pinned snapshot of another worksheet or a diff across worksheets.
+type InvalidSnapshotError struct {
+	Message string
+}
+
+func (e *InvalidSnapshotError) Error() string {
+	return e.Message
+}
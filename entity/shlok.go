@@ -6,4 +6,5 @@ type Shlok struct {
 	Title       map[string]string `bson:"title"`
 	Explanation map[string]string `bson:"explanation"`
 	Shlok       map[string]string `bson:"shlok"`
+	SourceHash  string            `bson:"source_hash"`
 }
@@ -0,0 +1,22 @@
+package entity
+
+// StitchedAudio is a generated prarthana audio and the position of each variant chapter in it,
+// in chapter order.
+type StitchedAudio struct {
+	Url         string
+	WaveformUrl string
+	DurationMs  int
+	Chapters    []ChapterOffset
+	Transcoded  TranscodedAudio
+}
+
+// TranscodedAudio is the bitrate ladder of a recording and the HLS master playlist over it.
+type TranscodedAudio struct {
+	HlsUrl     string
+	Renditions []AudioRendition
+}
+
+type ChapterOffset struct {
+	StartMs    int
+	DurationMs int
+}
@@ -9,4 +9,30 @@ type Stotra struct {
 	Duration               string            `bson:"duration"`
 	DurationInSeconds      int               `bson:"duration_in_seconds"`
 	DurationInMilliseconds int               `bson:"duration_in_milliseconds"`
+	AudioAnalysis          *AudioAnalysis    `bson:"audio_analysis,omitempty"`
+	ShlokTimings           []ShlokTiming     `bson:"shlok_timings"`
+	WaveformUrl            string            `bson:"waveform_url"`
+	HlsUrl                 string            `bson:"hls_url"`
+	Renditions             []AudioRendition  `bson:"renditions"`
+	SourceHash             string            `bson:"source_hash"`
+}
+
+// ShlokTiming is the time range of one shlok in the stotra audio. Source is "sheet" for
+// timestamps entered in the sheet and "detected" for boundaries placed in pauses of the audio.
+type ShlokTiming struct {
+	ShlokId string `bson:"shlok_id" json:"shlok_id"`
+	StartMs int    `bson:"start_ms" json:"start_ms"`
+	EndMs   int    `bson:"end_ms" json:"end_ms"`
+	Source  string `bson:"source" json:"source"`
+}
+
+// AudioAnalysis is the level analysis of a stotra recording. Warnings name the measures
+// outside the configured limits, so outliers stand out in the ingestion response.
+type AudioAnalysis struct {
+	IntegratedLufs    float64  `bson:"integrated_lufs" json:"integrated_lufs"`
+	PeakDbfs          float64  `bson:"peak_dbfs" json:"peak_dbfs"`
+	LeadingSilenceMs  int      `bson:"leading_silence_ms" json:"leading_silence_ms"`
+	TrailingSilenceMs int      `bson:"trailing_silence_ms" json:"trailing_silence_ms"`
+	ClippedSamples    int      `bson:"clipped_samples" json:"clipped_samples"`
+	Warnings          []string `bson:"warnings,omitempty" json:"warnings,omitempty"`
 }
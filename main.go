@@ -6,11 +6,18 @@ import (
 	"github.com/Out-Of-India-Theory/oit-go-commons/app"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/server"
+	"os"
 )
 
 func main() {
 	configuration := configuration.GetConfig()
 	ctx := context.Background()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := server.RunMigrations(ctx, configuration); err != nil {
+			panic(fmt.Sprintf("Unable to apply schema migrations : %v", err))
+		}
+		return
+	}
 	App, err := app.NewApp(ctx, configuration.ServerConfig)
 	if err != nil {
 		panic(fmt.Sprintf("Unable to initialize the app : %v", err))
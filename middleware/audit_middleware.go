@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/ingestion_audit"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/notifier"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+type AuditMiddleware struct {
+	auditService    ingestion_audit.Service
+	notifierService notifier.Service
+}
+
+func InitAuditMiddleware(auditService ingestion_audit.Service, notifierService notifier.Service) *AuditMiddleware {
+	return &AuditMiddleware{
+		auditService:    auditService,
+		notifierService: notifierService,
+	}
+}
+
+// Audit records the request in the ingestion audit log once the handler returns. It must run
+// after RequireRole so the caller is known. Handlers report what they read and wrote through
+// the audit trail in the request context. contentTypes are the content types the route
+// ingests; they pick the notification rules applied to the outcome.
+func (am *AuditMiddleware) Audit(contentTypes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		audit := entity.IngestionAudit{
+			Id:           uuid.NewString(),
+			Endpoint:     c.Request.Method + " " + c.FullPath(),
+			ContentTypes: contentTypes,
+			StartedAt:    time.Now().UTC(),
+		}
+		if principal := util.GetPrincipalFromContext(c.Request.Context()); principal != nil {
+			audit.Actor = *principal
+		}
+		audit.StartID, audit.EndID = peekIdRange(c)
+
+		trail := util.NewAuditTrail()
+		ctx := util.SetRunIdInContext(util.SetAuditTrailInContext(c.Request.Context(), trail), audit.Id)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		audit.FinishedAt = time.Now().UTC()
+		audit.Status = c.Writer.Status()
+		audit.Outcome = entity.AuditOutcomeSuccess
+		if audit.Status >= http.StatusBadRequest {
+			audit.Outcome = entity.AuditOutcomeFailure
+		}
+		if err := c.Errors.Last(); err != nil {
+			audit.Error = err.Error()
+		}
+		trail.Fill(&audit)
+		// the request may have been cancelled, the record must still be written and sent
+		ctx = context.WithoutCancel(c.Request.Context())
+		if err := am.auditService.Record(ctx, audit); err != nil {
+			log.Printf("Error recording ingestion audit for %s: %v\n", audit.Endpoint, err)
+		}
+		go am.notifierService.NotifyIngestion(ctx, audit)
+	}
+}
+
+// peekIdRange reads start_id and end_id from a JSON body without consuming it for the handler.
+func peekIdRange(c *gin.Context) (int, int) {
+	if c.Request.Body == nil || c.ContentType() != gin.MIMEJSON {
+		return 0, 0
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 0, 0
+	}
+	var request entity.IngestionRequest
+	_ = json.Unmarshal(body, &request)
+	return request.StartID, request.EndID
+}
@@ -1,34 +1,46 @@
 package middleware
 
 import (
-	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
-	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/zoho"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/auth"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
 	"github.com/gin-gonic/gin"
+	"log"
 	"net/http"
 )
 
 type AuthMiddleware struct {
-	config          *configuration.Configuration
-	zohoAuthService zoho.Service
+	authService auth.Service
 }
 
-func InitAuthMiddleware(config *configuration.Configuration, zohoAuthService zoho.Service) *AuthMiddleware {
+func InitAuthMiddleware(authService auth.Service) *AuthMiddleware {
 	return &AuthMiddleware{
-		config:          config,
-		zohoAuthService: zohoAuthService,
+		authService: authService,
 	}
 }
 
-func (am *AuthMiddleware) ZohoAuthMiddleware() gin.HandlerFunc {
+// RequireRole authenticates the caller and lets the request through only if their role
+// grants the required one. The principal is stored in the request context for handlers.
+func (am *AuthMiddleware) RequireRole(role entity.Role) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		accessToken, err := am.zohoAuthService.RefreshAccessToken()
+		principal, err := am.authService.Authenticate(c.Request.Context(), c.Request)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Failed to refresh access token"})
-			c.Abort()
+			log.Printf("Rejected %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"status":  http.StatusUnauthorized,
+				"message": "Authentication required",
+			})
 			return
 		}
-		c.Set("access-token", accessToken)
-		c.Request.Header.Set("zoho-access-token", accessToken)
+		if !principal.Role.Allows(role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"status":  http.StatusForbidden,
+				"message": "Role " + string(role) + " required",
+			})
+			return
+		}
+		c.Set("principal", principal)
+		c.Request = c.Request.WithContext(util.SetPrincipalInContext(c.Request.Context(), principal))
 		c.Next()
 	}
 }
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/cdn_invalidation"
+	"github.com/gin-gonic/gin"
+	"log"
+)
+
+type CdnMiddleware struct {
+	cdnInvalidationService cdn_invalidation.Service
+}
+
+func InitCdnMiddleware(cdnInvalidationService cdn_invalidation.Service) *CdnMiddleware {
+	return &CdnMiddleware{
+		cdnInvalidationService: cdnInvalidationService,
+	}
+}
+
+// InvalidateChangedAssets submits CDN invalidations for the assets the request overwrote once
+// the handler returns, whatever its outcome, since a failed run may have replaced some. It
+// must run after Audit, whose trail collects the assets and records the invalidations.
+func (cm *CdnMiddleware) InvalidateChangedAssets() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		// the request may have been cancelled, the overwritten assets must still be invalidated
+		ctx := context.WithoutCancel(c.Request.Context())
+		if err := cm.cdnInvalidationService.InvalidateChangedAssets(ctx); err != nil {
+			log.Printf("Error invalidating assets changed by %s %s: %v\n", c.Request.Method, c.FullPath(), err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+const (
+	kafka_backend  = "kafka"
+	nats_backend   = "nats"
+	sns_backend    = "sns"
+	memory_backend = "memory"
+)
+
+// InitPublisher returns the publisher selected by ChangeEventConfig.Backend.
+func InitPublisher(ctx context.Context, config configuration.Configuration) (Publisher, error) {
+	switch config.ChangeEventConfig.Backend {
+	case kafka_backend:
+		return InitKafkaPublisher(ctx, config)
+	case nats_backend:
+		return InitNatsPublisher(ctx, config)
+	case sns_backend:
+		return InitSnsPublisher(ctx, config)
+	case memory_backend, "":
+		return InitMemoryPublisher(ctx), nil
+	default:
+		return nil, fmt.Errorf("unknown change event backend: %s", config.ChangeEventConfig.Backend)
+	}
+}
+
+// eventKey identifies the document an event is about.
+func eventKey(event entity.ChangeEvent) string {
+	return event.ContentType + "/" + event.DocumentId
+}
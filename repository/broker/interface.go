@@ -0,0 +1,12 @@
+package broker
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+// Publisher sends change events to a message broker. Events of one document share a key so
+// brokers that partition keep them in order.
+type Publisher interface {
+	Publish(ctx context.Context, event entity.ChangeEvent) error
+}
@@ -0,0 +1,52 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// KafkaPublisher writes events to a Kafka topic keyed by document, waiting for all in-sync
+// replicas to acknowledge them.
+type KafkaPublisher struct {
+	logger *zap.Logger
+	writer *kafka.Writer
+}
+
+func InitKafkaPublisher(ctx context.Context, config configuration.Configuration) (*KafkaPublisher, error) {
+	eventConfig := config.ChangeEventConfig
+	if len(eventConfig.Brokers) == 0 || eventConfig.Topic == "" {
+		return nil, errors.New("kafka change events need brokers and a topic")
+	}
+	return &KafkaPublisher{
+		logger: logging.WithContext(ctx),
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(eventConfig.Brokers...),
+			Topic:        eventConfig.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		},
+	}, nil
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, event entity.ChangeEvent) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding change event %s: %w", event.Id, err)
+	}
+	err = p.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(eventKey(event)),
+		Value:   value,
+		Headers: []kafka.Header{{Key: "content_type", Value: []byte(event.ContentType)}},
+	})
+	if err != nil {
+		return fmt.Errorf("error publishing change event %s to kafka: %w", event.Id, err)
+	}
+	return nil
+}
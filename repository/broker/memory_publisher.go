@@ -0,0 +1,45 @@
+package broker
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"go.uber.org/zap"
+	"log"
+	"slices"
+	"sync"
+)
+
+// memoryCapacity is how many of the latest events MemoryPublisher keeps
+const memoryCapacity = 1000
+
+// MemoryPublisher stands in for a broker locally: it logs events and keeps the latest ones.
+type MemoryPublisher struct {
+	logger *zap.Logger
+	mu     sync.Mutex
+	events []entity.ChangeEvent
+}
+
+func InitMemoryPublisher(ctx context.Context) *MemoryPublisher {
+	return &MemoryPublisher{
+		logger: logging.WithContext(ctx),
+	}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, event entity.ChangeEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	log.Printf("Change event %s: %s %s version %d\n", event.Id, event.Action, eventKey(event), event.Version)
+	p.events = append(p.events, event)
+	if len(p.events) > memoryCapacity {
+		p.events = slices.Clone(p.events[len(p.events)-memoryCapacity:])
+	}
+	return nil
+}
+
+// Events returns the kept events, oldest first.
+func (p *MemoryPublisher) Events() []entity.ChangeEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.events)
+}
@@ -0,0 +1,59 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// NatsPublisher publishes events on "<Topic>.<content type>". The connection is retried in
+// the background, so the service starts while NATS is down and events wait in the outbox.
+type NatsPublisher struct {
+	logger *zap.Logger
+	conn   *nats.Conn
+	prefix string
+}
+
+func InitNatsPublisher(ctx context.Context, config configuration.Configuration) (*NatsPublisher, error) {
+	eventConfig := config.ChangeEventConfig
+	if eventConfig.NatsUrl == "" || eventConfig.Topic == "" {
+		return nil, errors.New("nats change events need a url and a subject prefix")
+	}
+	conn, err := nats.Connect(eventConfig.NatsUrl,
+		nats.Name(config.ServerConfig.AppName),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to nats: %w", err)
+	}
+	return &NatsPublisher{
+		logger: logging.WithContext(ctx),
+		conn:   conn,
+		prefix: eventConfig.Topic,
+	}, nil
+}
+
+// Publish waits for the server to have received the event, by flushing the connection.
+func (p *NatsPublisher) Publish(ctx context.Context, event entity.ChangeEvent) error {
+	if !p.conn.IsConnected() {
+		return fmt.Errorf("error publishing change event %s: not connected to nats", event.Id)
+	}
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding change event %s: %w", event.Id, err)
+	}
+	if err = p.conn.Publish(p.prefix+"."+event.ContentType, value); err != nil {
+		return fmt.Errorf("error publishing change event %s to nats: %w", event.Id, err)
+	}
+	if err = p.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("error flushing change event %s to nats: %w", event.Id, err)
+	}
+	return nil
+}
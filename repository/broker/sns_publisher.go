@@ -0,0 +1,68 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// SnsPublisher publishes events to an SNS topic, or a localstack one when Endpoint is set.
+// FIFO topics get the document as message group so its events stay in order.
+type SnsPublisher struct {
+	logger   *zap.Logger
+	client   *sns.Client
+	topicArn string
+}
+
+func InitSnsPublisher(ctx context.Context, config configuration.Configuration) (*SnsPublisher, error) {
+	eventConfig := config.ChangeEventConfig
+	if eventConfig.Topic == "" {
+		return nil, errors.New("sns change events need a topic arn")
+	}
+	awsCfg, err := awsConfig.LoadDefaultConfig(ctx, awsConfig.WithRegion(eventConfig.Region))
+	if err != nil {
+		return nil, fmt.Errorf("error loading aws config: %w", err)
+	}
+	client := sns.NewFromConfig(awsCfg, func(o *sns.Options) {
+		if eventConfig.Endpoint != "" {
+			o.BaseEndpoint = aws.String(eventConfig.Endpoint)
+		}
+	})
+	return &SnsPublisher{
+		logger:   logging.WithContext(ctx),
+		client:   client,
+		topicArn: eventConfig.Topic,
+	}, nil
+}
+
+func (p *SnsPublisher) Publish(ctx context.Context, event entity.ChangeEvent) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding change event %s: %w", event.Id, err)
+	}
+	input := &sns.PublishInput{
+		TopicArn: aws.String(p.topicArn),
+		Message:  aws.String(string(value)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"content_type": {DataType: aws.String("String"), StringValue: aws.String(event.ContentType)},
+		},
+	}
+	if strings.HasSuffix(p.topicArn, ".fifo") {
+		input.MessageGroupId = aws.String(eventKey(event))
+		input.MessageDeduplicationId = aws.String(event.Id)
+	}
+	if _, err = p.client.Publish(ctx, input); err != nil {
+		return fmt.Errorf("error publishing change event %s to sns: %w", event.Id, err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+)
+
+const (
+	cloudfront_client = "cloudfront"
+	fake_client       = "fake"
+)
+
+// InitClient returns the client selected by CdnConfig.Client.
+func InitClient(ctx context.Context, config configuration.Configuration) (Client, error) {
+	switch config.CdnConfig.Client {
+	case cloudfront_client:
+		return InitCloudFrontClient(ctx, config)
+	case fake_client, "":
+		return InitFakeClient(ctx), nil
+	default:
+		return nil, fmt.Errorf("unknown cdn client: %s", config.CdnConfig.Client)
+	}
+}
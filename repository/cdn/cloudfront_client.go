@@ -0,0 +1,77 @@
+package cdn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"go.uber.org/zap"
+)
+
+// CloudFront is a global service whose API is served from us-east-1
+const cloudfrontRegion = "us-east-1"
+
+// CloudFrontClient invalidates paths of one CloudFront distribution.
+type CloudFrontClient struct {
+	logger         *zap.Logger
+	client         *cloudfront.Client
+	distributionId string
+}
+
+func InitCloudFrontClient(ctx context.Context, config configuration.Configuration) (*CloudFrontClient, error) {
+	if config.CdnConfig.DistributionId == "" {
+		return nil, errors.New("cloudfront invalidation needs a distribution id")
+	}
+	awsCfg, err := awsConfig.LoadDefaultConfig(ctx, awsConfig.WithRegion(cloudfrontRegion))
+	if err != nil {
+		return nil, fmt.Errorf("error loading aws config: %w", err)
+	}
+	return &CloudFrontClient{
+		logger:         logging.WithContext(ctx),
+		client:         cloudfront.NewFromConfig(awsCfg),
+		distributionId: config.CdnConfig.DistributionId,
+	}, nil
+}
+
+func (c *CloudFrontClient) CreateInvalidation(ctx context.Context, callerReference string, paths []string) (Invalidation, error) {
+	output, err := c.client.CreateInvalidation(ctx, &cloudfront.CreateInvalidationInput{
+		DistributionId: aws.String(c.distributionId),
+		InvalidationBatch: &types.InvalidationBatch{
+			CallerReference: aws.String(callerReference),
+			Paths: &types.Paths{
+				Quantity: aws.Int32(int32(len(paths))),
+				Items:    paths,
+			},
+		},
+	})
+	if err != nil {
+		return Invalidation{}, fmt.Errorf("error creating cloudfront invalidation %s: %w", callerReference, err)
+	}
+	return cloudFrontInvalidation(output.Invalidation), nil
+}
+
+func (c *CloudFrontClient) GetInvalidation(ctx context.Context, id string) (Invalidation, error) {
+	output, err := c.client.GetInvalidation(ctx, &cloudfront.GetInvalidationInput{
+		DistributionId: aws.String(c.distributionId),
+		Id:             aws.String(id),
+	})
+	if err != nil {
+		return Invalidation{}, fmt.Errorf("error fetching cloudfront invalidation %s: %w", id, err)
+	}
+	return cloudFrontInvalidation(output.Invalidation), nil
+}
+
+// cloudFrontInvalidation maps CloudFront's "InProgress" and "Completed" statuses.
+func cloudFrontInvalidation(invalidation *types.Invalidation) Invalidation {
+	status := entity.InvalidationInProgress
+	if aws.ToString(invalidation.Status) == "Completed" {
+		status = entity.InvalidationCompleted
+	}
+	return Invalidation{Id: aws.ToString(invalidation.Id), Status: status}
+}
@@ -0,0 +1,55 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"go.uber.org/zap"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// FakeClient stands in for a CDN locally: it logs invalidations and completes them at once.
+type FakeClient struct {
+	logger *zap.Logger
+	mu     sync.Mutex
+	// invalidations are keyed by caller reference, ids are the references
+	invalidations map[string][]string
+}
+
+func InitFakeClient(ctx context.Context) *FakeClient {
+	return &FakeClient{
+		logger:        logging.WithContext(ctx),
+		invalidations: make(map[string][]string),
+	}
+}
+
+func (c *FakeClient) CreateInvalidation(ctx context.Context, callerReference string, paths []string) (Invalidation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.invalidations[callerReference]; ok && !slices.Equal(existing, paths) {
+		return Invalidation{}, fmt.Errorf("invalidation %s already exists with other paths", callerReference)
+	}
+	log.Printf("Invalidating %d path(s) as %s: %s\n", len(paths), callerReference, strings.Join(paths, ", "))
+	c.invalidations[callerReference] = slices.Clone(paths)
+	return Invalidation{Id: callerReference, Status: entity.InvalidationCompleted}, nil
+}
+
+func (c *FakeClient) GetInvalidation(ctx context.Context, id string) (Invalidation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.invalidations[id]; !ok {
+		return Invalidation{}, fmt.Errorf("invalidation %s: %w", id, entity.ErrInvalidationNotFound)
+	}
+	return Invalidation{Id: id, Status: entity.InvalidationCompleted}, nil
+}
+
+// Paths returns the paths invalidated under the caller reference.
+func (c *FakeClient) Paths(callerReference string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.invalidations[callerReference])
+}
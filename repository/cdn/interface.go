@@ -0,0 +1,17 @@
+package cdn
+
+import "context"
+
+// Client submits invalidations to a CDN. CreateInvalidation is idempotent for the same
+// callerReference and paths, so a failed batch can be resubmitted under its own id.
+type Client interface {
+	CreateInvalidation(ctx context.Context, callerReference string, paths []string) (Invalidation, error)
+	GetInvalidation(ctx context.Context, id string) (Invalidation, error)
+}
+
+// Invalidation is the CDN's view of a batch; Status is entity.InvalidationInProgress or
+// entity.InvalidationCompleted.
+type Invalidation struct {
+	Id     string
+	Status string
+}
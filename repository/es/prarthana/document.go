@@ -0,0 +1,111 @@
+package prarthana
+
+import "github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+
+type prarthanaDocument struct {
+	Id             string            `json:"id"`
+	TmpId          string            `json:"tmp_id"`
+	Title          map[string]string `json:"title"`
+	Description    map[string]string `json:"description"`
+	FestivalIds    []string          `json:"festival_ids"`
+	DeityIds       []string          `json:"deity_ids"`
+	Days           []int             `json:"days"`
+	IntentBased    bool              `json:"intent_based"`
+	AlbumArt       string            `json:"album_art"`
+	SearchKeywords []string          `json:"search_keywords"`
+}
+
+type deityDocument struct {
+	Id             string              `json:"id"`
+	TmpId          string              `json:"tmp_id"`
+	Slug           string              `json:"slug"`
+	Title          map[string]string   `json:"title"`
+	Description    map[string]string   `json:"description"`
+	Aliases        []string            `json:"aliases"`
+	AliasesV1      map[string][]string `json:"aliases_v1"`
+	FestivalIds    []string            `json:"festival_ids"`
+	Region         []string            `json:"region"`
+	Prarthanas     []string            `json:"prarthanas"`
+	Image          string              `json:"image"`
+	SearchKeywords []string            `json:"search_keywords"`
+}
+
+type stotraDocument struct {
+	Id                string            `json:"id"`
+	IntId             int               `json:"int_id"`
+	Title             map[string]string `json:"title"`
+	ShlokIds          []string          `json:"shlok_ids"`
+	DurationInSeconds int               `json:"duration_in_seconds"`
+	StotraUrl         string            `json:"stotra_url"`
+}
+
+type bulkDocument struct {
+	id   string
+	body interface{}
+}
+
+func prarthanaDocuments(prarthanas []entity.Prarthana) []bulkDocument {
+	docs := make([]bulkDocument, 0, len(prarthanas))
+	for _, p := range prarthanas {
+		docs = append(docs, bulkDocument{id: p.Id, body: prarthanaDocument{
+			Id:             p.Id,
+			TmpId:          p.TmpId,
+			Title:          nonEmpty(p.Title),
+			Description:    nonEmpty(p.Description),
+			FestivalIds:    p.FestivalIds,
+			DeityIds:       p.DeityIds,
+			Days:           p.Days,
+			IntentBased:    p.IntentBased,
+			AlbumArt:       p.UiInfo.AlbumArt,
+			SearchKeywords: p.SearchKeywords,
+		}})
+	}
+	return docs
+}
+
+func deityDocuments(deities []entity.DeityDocument) []bulkDocument {
+	docs := make([]bulkDocument, 0, len(deities))
+	for _, d := range deities {
+		docs = append(docs, bulkDocument{id: d.Id, body: deityDocument{
+			Id:             d.Id,
+			TmpId:          d.TmpId,
+			Slug:           d.Slug,
+			Title:          nonEmpty(d.Title),
+			Description:    nonEmpty(d.Description),
+			Aliases:        d.Aliases,
+			AliasesV1:      d.AliasesV1,
+			FestivalIds:    d.FestivalIds,
+			Region:         d.Region,
+			Prarthanas:     d.Prarthanas,
+			Image:          d.UIInfo.DefaultImage,
+			SearchKeywords: d.SearchKeywords,
+		}})
+	}
+	return docs
+}
+
+func stotraDocuments(stotras []entity.Stotra) []bulkDocument {
+	docs := make([]bulkDocument, 0, len(stotras))
+	for _, s := range stotras {
+		docs = append(docs, bulkDocument{id: s.ID, body: stotraDocument{
+			Id:                s.ID,
+			IntId:             s.IntId,
+			Title:             nonEmpty(s.Title),
+			ShlokIds:          s.ShlokIds,
+			DurationInSeconds: s.DurationInSeconds,
+			StotraUrl:         s.StotraUrl,
+		}})
+	}
+	return docs
+}
+
+// nonEmpty drops blank translations so they are not indexed as empty terms.
+func nonEmpty(values map[string]string) map[string]string {
+	result := make(map[string]string)
+	for lang, value := range values {
+		if value != "" {
+			result[lang] = value
+		}
+	}
+	return result
+}
@@ -1 +1,13 @@
 package prarthana
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type ElasticRepository interface {
+	IndexPrarthanas(ctx context.Context, prarthanas []entity.Prarthana) error
+	IndexDeities(ctx context.Context, deities []entity.DeityDocument) error
+	IndexStotras(ctx context.Context, stotras []entity.Stotra) error
+	Reindex(ctx context.Context, prarthanas []entity.Prarthana, deities []entity.DeityDocument, stotras []entity.Stotra) error
+}
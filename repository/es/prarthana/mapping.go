@@ -0,0 +1,114 @@
+package prarthana
+
+import "fmt"
+
+// languageAnalyzers maps the language keys used in Title/Description/AliasesV1 to the
+// analyzer applied to them. Anything not listed falls back to the generic indic analyzer.
+var languageAnalyzers = map[string]string{
+	"default": "latin",
+	"en":      "latin",
+	"hi":      "devanagari",
+	"mr":      "devanagari",
+	"bn":      "bengali_script",
+	"as":      "bengali_script",
+}
+
+var multilingualFields = []string{"title", "description", "aliases_v1"}
+
+func indexSettings() map[string]interface{} {
+	return map[string]interface{}{
+		"analysis": map[string]interface{}{
+			"analyzer": map[string]interface{}{
+				"latin": map[string]interface{}{
+					"tokenizer": "standard",
+					"filter":    []string{"lowercase", "asciifolding"},
+				},
+				"devanagari": map[string]interface{}{
+					"tokenizer": "standard",
+					"filter":    []string{"lowercase", "decimal_digit", "indic_normalization", "hindi_normalization"},
+				},
+				"bengali_script": map[string]interface{}{
+					"tokenizer": "standard",
+					"filter":    []string{"lowercase", "decimal_digit", "indic_normalization", "bengali_normalization"},
+				},
+				"indic": map[string]interface{}{
+					"tokenizer": "standard",
+					"filter":    []string{"lowercase", "decimal_digit", "indic_normalization"},
+				},
+			},
+		},
+	}
+}
+
+// indexMappings builds dynamic templates so every per-language key of the multilingual
+// fields (title.hi, description.ta, aliases_v1.kn, ...) is analyzed for its script.
+func indexMappings(properties map[string]interface{}) map[string]interface{} {
+	var templates []map[string]interface{}
+	for _, field := range multilingualFields {
+		for lang, analyzer := range languageAnalyzers {
+			templates = append(templates, textTemplate(fmt.Sprintf("%s_%s", field, lang), fmt.Sprintf("%s.%s", field, lang), analyzer))
+		}
+		templates = append(templates, textTemplate(fmt.Sprintf("%s_other", field), fmt.Sprintf("%s.*", field), "indic"))
+	}
+	return map[string]interface{}{
+		"dynamic_templates": templates,
+		"properties":        properties,
+	}
+}
+
+func textTemplate(name, pathMatch, analyzer string) map[string]interface{} {
+	return map[string]interface{}{
+		name: map[string]interface{}{
+			"path_match": pathMatch,
+			"mapping": map[string]interface{}{
+				"type":     "text",
+				"analyzer": analyzer,
+				"fields": map[string]interface{}{
+					"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256},
+				},
+			},
+		},
+	}
+}
+
+var keyword = map[string]interface{}{"type": "keyword"}
+
+// searchKeywords mixes scripts and their transliterations, so it gets the script-agnostic analyzer.
+var searchKeywords = map[string]interface{}{
+	"type":     "text",
+	"analyzer": "indic",
+	"fields": map[string]interface{}{
+		"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256},
+	},
+}
+
+var indexProperties = map[string]map[string]interface{}{
+	prarthana_index: {
+		"id":              keyword,
+		"tmp_id":          keyword,
+		"festival_ids":    keyword,
+		"deity_ids":       keyword,
+		"days":            map[string]interface{}{"type": "integer"},
+		"intent_based":    map[string]interface{}{"type": "boolean"},
+		"album_art":       map[string]interface{}{"type": "keyword", "index": false},
+		"search_keywords": searchKeywords,
+	},
+	deity_index: {
+		"id":              keyword,
+		"tmp_id":          keyword,
+		"slug":            keyword,
+		"aliases":         map[string]interface{}{"type": "text", "analyzer": "latin"},
+		"festival_ids":    keyword,
+		"region":          keyword,
+		"prarthanas":      keyword,
+		"image":           map[string]interface{}{"type": "keyword", "index": false},
+		"search_keywords": searchKeywords,
+	},
+	stotra_index: {
+		"id":                  keyword,
+		"int_id":              map[string]interface{}{"type": "integer"},
+		"shlok_ids":           keyword,
+		"duration_in_seconds": map[string]interface{}{"type": "integer"},
+		"stotra_url":          map[string]interface{}{"type": "keyword", "index": false},
+	},
+}
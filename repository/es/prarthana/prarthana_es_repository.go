@@ -0,0 +1,277 @@
+package prarthana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"go.uber.org/zap"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	prarthana_index = "prarthanas"
+	deity_index     = "deities"
+	stotra_index    = "stotras"
+
+	bulkBatchSize = 500
+)
+
+type PrarthanaElasticRepository struct {
+	logger     *zap.Logger
+	config     configuration.ElasticConfig
+	httpClient *http.Client
+}
+
+func InitPrarthanaElasticRepository(ctx context.Context, config configuration.Configuration, httpClient *http.Client) *PrarthanaElasticRepository {
+	return &PrarthanaElasticRepository{
+		logger:     logging.WithContext(ctx),
+		config:     config.ElasticConfig,
+		httpClient: httpClient,
+	}
+}
+
+func (r *PrarthanaElasticRepository) IndexPrarthanas(ctx context.Context, prarthanas []entity.Prarthana) error {
+	return r.indexDocuments(ctx, prarthana_index, prarthanaDocuments(prarthanas))
+}
+
+func (r *PrarthanaElasticRepository) IndexDeities(ctx context.Context, deities []entity.DeityDocument) error {
+	return r.indexDocuments(ctx, deity_index, deityDocuments(deities))
+}
+
+func (r *PrarthanaElasticRepository) IndexStotras(ctx context.Context, stotras []entity.Stotra) error {
+	return r.indexDocuments(ctx, stotra_index, stotraDocuments(stotras))
+}
+
+// Reindex rebuilds every index from scratch into fresh versioned indices and then
+// atomically moves the aliases, so searches keep hitting the old data until the swap.
+func (r *PrarthanaElasticRepository) Reindex(ctx context.Context, prarthanas []entity.Prarthana, deities []entity.DeityDocument, stotras []entity.Stotra) error {
+	if err := r.rebuildIndex(ctx, prarthana_index, prarthanaDocuments(prarthanas)); err != nil {
+		return err
+	}
+	if err := r.rebuildIndex(ctx, deity_index, deityDocuments(deities)); err != nil {
+		return err
+	}
+	return r.rebuildIndex(ctx, stotra_index, stotraDocuments(stotras))
+}
+
+func (r *PrarthanaElasticRepository) indexDocuments(ctx context.Context, name string, docs []bulkDocument) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	if err := r.ensureAlias(ctx, name); err != nil {
+		return err
+	}
+	return r.bulkIndex(ctx, r.aliasName(name), docs)
+}
+
+func (r *PrarthanaElasticRepository) rebuildIndex(ctx context.Context, name string, docs []bulkDocument) error {
+	alias := r.aliasName(name)
+	oldIndices, err := r.aliasIndices(ctx, alias)
+	if err != nil {
+		return err
+	}
+	newIndex := versionedIndexName(alias)
+	if err := r.createIndex(ctx, name, newIndex); err != nil {
+		return err
+	}
+	if err := r.bulkIndex(ctx, newIndex, docs); err != nil {
+		return err
+	}
+
+	actions := make([]map[string]interface{}, 0, len(oldIndices)+1)
+	for _, index := range oldIndices {
+		actions = append(actions, map[string]interface{}{"remove": map[string]string{"index": index, "alias": alias}})
+	}
+	actions = append(actions, map[string]interface{}{"add": map[string]string{"index": newIndex, "alias": alias}})
+	if _, err := r.do(ctx, http.MethodPost, "/_aliases", map[string]interface{}{"actions": actions}); err != nil {
+		return fmt.Errorf("error swapping alias %s to %s: %w", alias, newIndex, err)
+	}
+
+	for _, index := range oldIndices {
+		if _, err := r.do(ctx, http.MethodDelete, "/"+index, nil); err != nil {
+			log.Printf("Failed to delete old index %s: %v\n", index, err)
+		}
+	}
+	log.Printf("Reindexed %d documents into %s\n", len(docs), newIndex)
+	return nil
+}
+
+// ensureAlias creates the first versioned index behind an alias if the alias does not exist yet.
+func (r *PrarthanaElasticRepository) ensureAlias(ctx context.Context, name string) error {
+	alias := r.aliasName(name)
+	indices, err := r.aliasIndices(ctx, alias)
+	if err != nil {
+		return err
+	}
+	if len(indices) > 0 {
+		return nil
+	}
+	index := versionedIndexName(alias)
+	if err := r.createIndex(ctx, name, index); err != nil {
+		return err
+	}
+	actions := []map[string]interface{}{{"add": map[string]string{"index": index, "alias": alias}}}
+	if _, err := r.do(ctx, http.MethodPost, "/_aliases", map[string]interface{}{"actions": actions}); err != nil {
+		return fmt.Errorf("error creating alias %s: %w", alias, err)
+	}
+	return nil
+}
+
+func (r *PrarthanaElasticRepository) aliasIndices(ctx context.Context, alias string) ([]string, error) {
+	req, err := r.newRequest(ctx, http.MethodGet, "/_alias/"+alias, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching alias %s: %w", alias, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading alias %s: %w", alias, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching alias %s: %s", alias, string(body))
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("error parsing alias %s: %w", alias, err)
+	}
+	indices := make([]string, 0, len(result))
+	for index := range result {
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
+func (r *PrarthanaElasticRepository) createIndex(ctx context.Context, name, index string) error {
+	body := map[string]interface{}{
+		"settings": indexSettings(),
+		"mappings": indexMappings(indexProperties[name]),
+	}
+	if _, err := r.do(ctx, http.MethodPut, "/"+index, body); err != nil {
+		return fmt.Errorf("error creating index %s: %w", index, err)
+	}
+	return nil
+}
+
+func (r *PrarthanaElasticRepository) bulkIndex(ctx context.Context, index string, docs []bulkDocument) error {
+	for start := 0; start < len(docs); start += bulkBatchSize {
+		end := start + bulkBatchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		for _, doc := range docs[start:end] {
+			action := map[string]interface{}{"index": map[string]string{"_index": index, "_id": doc.id}}
+			if err := encoder.Encode(action); err != nil {
+				return fmt.Errorf("error encoding bulk action: %w", err)
+			}
+			if err := encoder.Encode(doc.body); err != nil {
+				return fmt.Errorf("error encoding document %s: %w", doc.id, err)
+			}
+		}
+
+		req, err := r.newRequest(ctx, http.MethodPost, "/_bulk", &buf)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		body, err := r.send(req)
+		if err != nil {
+			return fmt.Errorf("error bulk indexing into %s: %w", index, err)
+		}
+		var result struct {
+			Errors bool `json:"errors"`
+			Items  []map[string]struct {
+				Id     string          `json:"_id"`
+				Status int             `json:"status"`
+				Error  json.RawMessage `json:"error"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return fmt.Errorf("error parsing bulk response: %w", err)
+		}
+		if result.Errors {
+			for _, item := range result.Items {
+				for _, status := range item {
+					if status.Status >= http.StatusMultipleChoices {
+						return fmt.Errorf("error indexing document %s into %s: %s", status.Id, index, string(status.Error))
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// versionedIndexName gives each physical index behind an alias a unique, sortable name.
+func versionedIndexName(alias string) string {
+	return fmt.Sprintf("%s_%d", alias, time.Now().UTC().UnixMilli())
+}
+
+func (r *PrarthanaElasticRepository) aliasName(name string) string {
+	if r.config.IndexPrefix == "" {
+		return name
+	}
+	return fmt.Sprintf("%s_%s", r.config.IndexPrefix, name)
+}
+
+func (r *PrarthanaElasticRepository) do(ctx context.Context, method, path string, payload interface{}) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := r.newRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return r.send(req)
+}
+
+func (r *PrarthanaElasticRepository) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(r.config.Address, "/")+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if r.config.Username != "" {
+		req.SetBasicAuth(r.config.Username, r.config.Password)
+	}
+	return req, nil
+}
+
+func (r *PrarthanaElasticRepository) send(req *http.Request) ([]byte, error) {
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("error response from elasticsearch: %s", string(body))
+	}
+	return body, nil
+}
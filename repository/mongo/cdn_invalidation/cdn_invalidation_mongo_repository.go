@@ -0,0 +1,90 @@
+package cdn_invalidation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	mongoCommons "github.com/Out-Of-India-Theory/oit-go-commons/mongo"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const cdn_invalidation_collection = "cdn_invalidations"
+
+type CdnInvalidationMongoRepository struct {
+	logger                 *zap.Logger
+	invalidationCollection *mongo.Collection
+}
+
+func InitCdnInvalidationMongoRepository(ctx context.Context, config configuration.Configuration) *CdnInvalidationMongoRepository {
+	mongoClient := mongoCommons.InitMongoClient(ctx, config.MongoConfig)
+	return &CdnInvalidationMongoRepository{
+		logger:                 logging.WithContext(ctx),
+		invalidationCollection: mongoClient.Database(config.MongoConfig.Database).Collection(cdn_invalidation_collection),
+	}
+}
+
+func (r *CdnInvalidationMongoRepository) Insert(ctx context.Context, invalidation entity.CdnInvalidation) error {
+	if _, err := r.invalidationCollection.InsertOne(ctx, invalidation); err != nil {
+		return fmt.Errorf("error storing cdn invalidation %s: %w", invalidation.Id, err)
+	}
+	return nil
+}
+
+func (r *CdnInvalidationMongoRepository) Update(ctx context.Context, invalidation entity.CdnInvalidation) error {
+	if _, err := r.invalidationCollection.ReplaceOne(ctx, bson.M{"_id": invalidation.Id}, invalidation); err != nil {
+		return fmt.Errorf("error updating cdn invalidation %s: %w", invalidation.Id, err)
+	}
+	return nil
+}
+
+func (r *CdnInvalidationMongoRepository) GetById(ctx context.Context, id string) (entity.CdnInvalidation, error) {
+	var invalidation entity.CdnInvalidation
+	err := r.invalidationCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&invalidation)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return entity.CdnInvalidation{}, entity.ErrInvalidationNotFound
+	}
+	if err != nil {
+		return entity.CdnInvalidation{}, fmt.Errorf("error fetching cdn invalidation %s: %w", id, err)
+	}
+	return invalidation, nil
+}
+
+// Find returns one page of invalidations, newest first.
+func (r *CdnInvalidationMongoRepository) Find(ctx context.Context, query entity.CdnInvalidationQuery) ([]entity.CdnInvalidation, int64, error) {
+	filter := bson.M{}
+	if query.Status != "" {
+		filter["status"] = query.Status
+	}
+	if query.RunId != "" {
+		filter["run_id"] = query.RunId
+	}
+	if query.Path != "" {
+		filter["paths"] = query.Path
+	}
+	total, err := r.invalidationCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting cdn invalidations: %w", err)
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((query.Page - 1) * query.PageSize)).
+		SetLimit(int64(query.PageSize))
+	cursor, err := r.invalidationCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching cdn invalidations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	invalidations := []entity.CdnInvalidation{}
+	if err = cursor.All(ctx, &invalidations); err != nil {
+		return nil, 0, fmt.Errorf("error decoding cdn invalidations: %w", err)
+	}
+	return invalidations, total, nil
+}
@@ -0,0 +1,13 @@
+package cdn_invalidation
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type MongoRepository interface {
+	Insert(ctx context.Context, invalidation entity.CdnInvalidation) error
+	Update(ctx context.Context, invalidation entity.CdnInvalidation) error
+	GetById(ctx context.Context, id string) (entity.CdnInvalidation, error)
+	Find(ctx context.Context, query entity.CdnInvalidationQuery) ([]entity.CdnInvalidation, int64, error)
+}
@@ -0,0 +1,152 @@
+package change_outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	mongoCommons "github.com/Out-Of-India-Theory/oit-go-commons/mongo"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	change_outbox_collection   = "change_outbox"
+	content_version_collection = "content_versions"
+)
+
+type ChangeOutboxMongoRepository struct {
+	logger            *zap.Logger
+	outboxCollection  *mongo.Collection
+	versionCollection *mongo.Collection
+}
+
+func InitChangeOutboxMongoRepository(ctx context.Context, config configuration.Configuration) *ChangeOutboxMongoRepository {
+	mongoClient := mongoCommons.InitMongoClient(ctx, config.MongoConfig)
+	return InitChangeOutboxMongoRepositoryForDatabase(ctx, mongoClient.Database(config.MongoConfig.Database))
+}
+
+// InitChangeOutboxMongoRepositoryForDatabase writes through the client of database, so
+// events can be appended in a transaction of another repository on that client.
+func InitChangeOutboxMongoRepositoryForDatabase(ctx context.Context, database *mongo.Database) *ChangeOutboxMongoRepository {
+	return &ChangeOutboxMongoRepository{
+		logger:            logging.WithContext(ctx),
+		outboxCollection:  database.Collection(change_outbox_collection),
+		versionCollection: database.Collection(content_version_collection),
+	}
+}
+
+// Append stores the event as pending, with the next version of its document. Within a
+// transaction both writes are part of it.
+func (r *ChangeOutboxMongoRepository) Append(ctx context.Context, event entity.ChangeEvent) error {
+	version, err := r.nextVersion(ctx, event.ContentType, event.DocumentId)
+	if err != nil {
+		return err
+	}
+	event.Id = uuid.NewString()
+	event.Version = version
+	entry := entity.OutboxEntry{
+		Event:         event,
+		Status:        entity.ChangeEventPending,
+		NextAttemptAt: event.OccurredAt,
+	}
+	if _, err := r.outboxCollection.InsertOne(ctx, entry); err != nil {
+		return fmt.Errorf("error adding change event of %s/%s to the outbox: %w", event.ContentType, event.DocumentId, err)
+	}
+	return nil
+}
+
+// nextVersion increments the change counter of the document in content_versions.
+func (r *ChangeOutboxMongoRepository) nextVersion(ctx context.Context, contentType string, documentId string) (int64, error) {
+	var counter struct {
+		Version int64 `bson:"version"`
+	}
+	err := r.versionCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": contentType + "/" + documentId},
+		bson.M{"$inc": bson.M{"version": 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return 0, fmt.Errorf("error incrementing version of %s/%s: %w", contentType, documentId, err)
+	}
+	return counter.Version, nil
+}
+
+// FindPending returns the oldest pending events in the order they occurred.
+func (r *ChangeOutboxMongoRepository) FindPending(ctx context.Context, limit int) ([]entity.OutboxEntry, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "occurred_at", Value: 1}, {Key: "version", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.outboxCollection.Find(ctx, bson.M{"status": entity.ChangeEventPending}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching pending change events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []entity.OutboxEntry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("error decoding pending change events: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *ChangeOutboxMongoRepository) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	_, err := r.outboxCollection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"status": entity.ChangeEventPublished, "published_at": publishedAt}, "$unset": bson.M{"last_error": ""}},
+	)
+	if err != nil {
+		return fmt.Errorf("error marking change event %s published: %w", id, err)
+	}
+	return nil
+}
+
+func (r *ChangeOutboxMongoRepository) MarkFailed(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time) error {
+	_, err := r.outboxCollection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"attempts": attempts, "last_error": lastError, "next_attempt_at": nextAttemptAt}},
+	)
+	if err != nil {
+		return fmt.Errorf("error recording failed publish of change event %s: %w", id, err)
+	}
+	return nil
+}
+
+// Find returns one page of outbox entries, newest first.
+func (r *ChangeOutboxMongoRepository) Find(ctx context.Context, query entity.OutboxQuery) ([]entity.OutboxEntry, int64, error) {
+	filter := bson.M{}
+	if query.Status != "" {
+		filter["status"] = query.Status
+	}
+	if query.ContentType != "" {
+		filter["content_type"] = query.ContentType
+	}
+	if query.DocumentId != "" {
+		filter["document_id"] = query.DocumentId
+	}
+	total, err := r.outboxCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting change events: %w", err)
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "occurred_at", Value: -1}, {Key: "version", Value: -1}}).
+		SetSkip(int64((query.Page - 1) * query.PageSize)).
+		SetLimit(int64(query.PageSize))
+	cursor, err := r.outboxCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching change events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []entity.OutboxEntry{}
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, 0, fmt.Errorf("error decoding change events: %w", err)
+	}
+	return entries, total, nil
+}
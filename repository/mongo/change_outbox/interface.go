@@ -0,0 +1,15 @@
+package change_outbox
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"time"
+)
+
+type MongoRepository interface {
+	Append(ctx context.Context, event entity.ChangeEvent) error
+	FindPending(ctx context.Context, limit int) ([]entity.OutboxEntry, error)
+	MarkPublished(ctx context.Context, id string, publishedAt time.Time) error
+	MarkFailed(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time) error
+	Find(ctx context.Context, query entity.OutboxQuery) ([]entity.OutboxEntry, int64, error)
+}
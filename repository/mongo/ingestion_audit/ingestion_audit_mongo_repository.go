@@ -0,0 +1,82 @@
+package ingestion_audit
+
+import (
+	"context"
+	"fmt"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	mongoCommons "github.com/Out-Of-India-Theory/oit-go-commons/mongo"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const ingestion_audit_collection = "ingestion_audit"
+
+type IngestionAuditMongoRepository struct {
+	logger          *zap.Logger
+	auditCollection *mongo.Collection
+}
+
+func InitIngestionAuditMongoRepository(ctx context.Context, config configuration.Configuration) *IngestionAuditMongoRepository {
+	mongoClient := mongoCommons.InitMongoClient(ctx, config.MongoConfig)
+	return &IngestionAuditMongoRepository{
+		logger:          logging.WithContext(ctx),
+		auditCollection: mongoClient.Database(config.MongoConfig.Database).Collection(ingestion_audit_collection),
+	}
+}
+
+// Insert appends an audit record; records are never updated or deleted.
+func (r *IngestionAuditMongoRepository) Insert(ctx context.Context, audit entity.IngestionAudit) error {
+	if _, err := r.auditCollection.InsertOne(ctx, audit); err != nil {
+		return fmt.Errorf("error inserting ingestion audit %s: %w", audit.Id, err)
+	}
+	return nil
+}
+
+// Find returns one page of the records matching the query, newest first, and the total
+// number of matching records.
+func (r *IngestionAuditMongoRepository) Find(ctx context.Context, query entity.IngestionAuditQuery) ([]entity.IngestionAudit, int64, error) {
+	filter := bson.M{}
+	if query.Subject != "" {
+		filter["actor.subject"] = query.Subject
+	}
+	if query.Endpoint != "" {
+		filter["endpoint"] = query.Endpoint
+	}
+	if query.Outcome != "" {
+		filter["outcome"] = query.Outcome
+	}
+	startedAt := bson.M{}
+	if !query.From.IsZero() {
+		startedAt["$gte"] = query.From
+	}
+	if !query.To.IsZero() {
+		startedAt["$lt"] = query.To
+	}
+	if len(startedAt) > 0 {
+		filter["started_at"] = startedAt
+	}
+
+	total, err := r.auditCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting ingestion audits: %w", err)
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "started_at", Value: -1}}).
+		SetSkip(int64((query.Page - 1) * query.PageSize)).
+		SetLimit(int64(query.PageSize))
+	cursor, err := r.auditCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching ingestion audits: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	audits := []entity.IngestionAudit{}
+	if err = cursor.All(ctx, &audits); err != nil {
+		return nil, 0, fmt.Errorf("error decoding ingestion audits: %w", err)
+	}
+	return audits, total, nil
+}
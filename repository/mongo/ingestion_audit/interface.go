@@ -0,0 +1,11 @@
+package ingestion_audit
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type MongoRepository interface {
+	Insert(ctx context.Context, audit entity.IngestionAudit) error
+	Find(ctx context.Context, query entity.IngestionAuditQuery) ([]entity.IngestionAudit, int64, error)
+}
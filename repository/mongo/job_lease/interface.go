@@ -0,0 +1,12 @@
+package job_lease
+
+import (
+	"context"
+	"time"
+)
+
+type MongoRepository interface {
+	Acquire(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error)
+	Renew(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name string, holder string) error
+}
@@ -0,0 +1,73 @@
+package job_lease
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	mongoCommons "github.com/Out-Of-India-Theory/oit-go-commons/mongo"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const job_lease_collection = "job_leases"
+
+type JobLeaseMongoRepository struct {
+	logger          *zap.Logger
+	leaseCollection *mongo.Collection
+}
+
+func InitJobLeaseMongoRepository(ctx context.Context, config configuration.Configuration) *JobLeaseMongoRepository {
+	mongoClient := mongoCommons.InitMongoClient(ctx, config.MongoConfig)
+	return &JobLeaseMongoRepository{
+		logger:          logging.WithContext(ctx),
+		leaseCollection: mongoClient.Database(config.MongoConfig.Database).Collection(job_lease_collection),
+	}
+}
+
+// Acquire takes the lease if nobody holds it or the last holder's lease has expired. When
+// another instance holds it the upsert collides with its document on _id and false is
+// returned.
+func (r *JobLeaseMongoRepository) Acquire(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	_, err := r.leaseCollection.UpdateOne(ctx,
+		bson.M{"_id": name, "expires_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"holder": holder, "acquired_at": now, "expires_at": now.Add(ttl)}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error acquiring lease %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// Renew extends a lease still held by holder; false means it was lost to another instance.
+func (r *JobLeaseMongoRepository) Renew(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error) {
+	result, err := r.leaseCollection.UpdateOne(ctx,
+		bson.M{"_id": name, "holder": holder},
+		bson.M{"$set": bson.M{"expires_at": time.Now().UTC().Add(ttl)}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("error renewing lease %s: %w", name, err)
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// Release expires the lease right away if holder still has it.
+func (r *JobLeaseMongoRepository) Release(ctx context.Context, name string, holder string) error {
+	_, err := r.leaseCollection.UpdateOne(ctx,
+		bson.M{"_id": name, "holder": holder},
+		bson.M{"$set": bson.M{"expires_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		return fmt.Errorf("error releasing lease %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package prarthana_data
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// writeDocument runs write, which stores one document and returns the change it made. When
+// change events are enabled, the write and the change event of the document are committed in
+// one transaction, so a stored change always has its event. The change is added to the
+// request's audit trail once committed, since a transaction may run write more than once.
+func (r *PrarthanaDataMongoRepository) writeDocument(ctx context.Context, write func(ctx context.Context) (entity.DocumentChange, error)) error {
+	var change entity.DocumentChange
+	err := r.inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if change, err = write(ctx); err != nil {
+			return err
+		}
+		return r.appendChangeEvent(ctx, change)
+	})
+	if err != nil {
+		return err
+	}
+	util.RecordChange(ctx, change.Collection, change.Id, change.Action, change.Fields)
+	return nil
+}
+
+// inTransaction runs fn in a transaction when change events are enabled, which needs a
+// replica set; otherwise fn runs on its own. fn is retried on transient errors.
+func (r *PrarthanaDataMongoRepository) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !r.emitChanges {
+		return fn(ctx)
+	}
+	session, err := r.mongoClient.StartSession()
+	if err != nil {
+		return fmt.Errorf("error starting mongo session: %w", err)
+	}
+	defer session.EndSession(ctx)
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx)
+	})
+	return err
+}
+
+// appendChangeEvent adds the change event of a document write to the outbox, when change
+// events are enabled and the document changed.
+func (r *PrarthanaDataMongoRepository) appendChangeEvent(ctx context.Context, change entity.DocumentChange) error {
+	if !r.emitChanges || change.Action == entity.ChangeUnchanged {
+		return nil
+	}
+	return r.changeOutbox.Append(ctx, entity.ChangeEvent{
+		ContentType:   change.Collection,
+		DocumentId:    change.Id,
+		Action:        change.Action,
+		ChangedFields: change.Fields,
+		RunId:         util.GetRunIdFromContext(ctx),
+		OccurredAt:    time.Now().UTC(),
+	})
+}
+
+// documentChange summarizes the write of a document. before is nil for inserts; for
+// replacements fields missing from after count as changed, for $set updates they are left
+// alone and so are not.
+func documentChange(collection string, id string, before bson.Raw, after interface{}, replace bool) (entity.DocumentChange, error) {
+	change := entity.DocumentChange{Collection: collection, Id: id, Action: entity.ChangeInserted}
+	if before == nil {
+		return change, nil
+	}
+	fields, err := changedFields(before, after, replace)
+	if err != nil {
+		return entity.DocumentChange{}, err
+	}
+	change.Action = entity.ChangeUpdated
+	change.Fields = fields
+	if len(fields) == 0 {
+		change.Action = entity.ChangeUnchanged
+	}
+	return change, nil
+}
+
+func changedFields(before bson.Raw, after interface{}, replace bool) ([]string, error) {
+	afterRaw, err := bson.Marshal(after)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling document for change summary: %w", err)
+	}
+	afterElements, err := bson.Raw(afterRaw).Elements()
+	if err != nil {
+		return nil, fmt.Errorf("error reading document for change summary: %w", err)
+	}
+	var fields []string
+	seen := make(map[string]bool)
+	for _, element := range afterElements {
+		key := element.Key()
+		seen[key] = true
+		old, err := before.LookupErr(key)
+		value := element.Value()
+		if err != nil || old.Type != value.Type || !bytes.Equal(old.Value, value.Value) {
+			fields = append(fields, key)
+		}
+	}
+	if replace {
+		beforeElements, err := before.Elements()
+		if err != nil {
+			return nil, fmt.Errorf("error reading stored document for change summary: %w", err)
+		}
+		for _, element := range beforeElements {
+			if !seen[element.Key()] {
+				fields = append(fields, element.Key())
+			}
+		}
+	}
+	return fields, nil
+}
+
+// recordRaw is the document a FindOne or FindOneAndReplace matched, or an empty document if
+// it cannot be read, which reports every field as changed.
+func recordRaw(result *mongo.SingleResult) bson.Raw {
+	raw, err := result.Raw()
+	if err != nil {
+		return bson.Raw{5, 0, 0, 0, 0}
+	}
+	return raw
+}
@@ -0,0 +1,175 @@
+package prarthana_data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindContent returns one page of the documents of a content type matching the query and
+// the total number of matching documents. Only the given fields are returned, or every
+// field when fields is empty.
+func (r *PrarthanaDataMongoRepository) FindContent(ctx context.Context, contentType string, query entity.ContentQuery, fields []string) ([]entity.ContentDocument, int64, error) {
+	collection, err := r.contentCollection(contentType)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter := contentFilter(contentType, query)
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting %s: %w", contentType, err)
+	}
+	opts := options.Find().
+		SetSort(contentSort(contentType)).
+		SetSkip(int64((query.Page - 1) * query.PageSize)).
+		SetLimit(int64(query.PageSize))
+	if len(fields) > 0 {
+		opts.SetProjection(projection(fields))
+	}
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching %s: %w", contentType, err)
+	}
+	defer cursor.Close(ctx)
+
+	documents := []entity.ContentDocument{}
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, 0, fmt.Errorf("error decoding %s: %w", contentType, err)
+	}
+	return documents, total, nil
+}
+
+// GetContentByKey looks a document up by _id or by the content type's other identifiers:
+// int_id for shloks and stotras, TmpId for prarthanas, TmpId and slug for deities.
+func (r *PrarthanaDataMongoRepository) GetContentByKey(ctx context.Context, contentType string, key string, fields []string) (entity.ContentDocument, error) {
+	collection, err := r.contentCollection(contentType)
+	if err != nil {
+		return nil, err
+	}
+	keys := bson.A{bson.M{"_id": key}}
+	switch contentType {
+	case entity.ContentShloks, entity.ContentStotras:
+		if intId, err := strconv.Atoi(key); err == nil {
+			keys = append(keys, bson.M{"int_id": intId})
+		}
+	case entity.ContentPrarthanas:
+		keys = append(keys, bson.M{"TmpId": key})
+	case entity.ContentDeities:
+		keys = append(keys, bson.M{"TmpId": key}, bson.M{"slug": key})
+	}
+	opts := options.FindOne()
+	if len(fields) > 0 {
+		opts.SetProjection(projection(fields))
+	}
+	var document entity.ContentDocument
+	err = collection.FindOne(ctx, bson.M{"$or": keys}, opts).Decode(&document)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, entity.ErrContentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s %s: %w", contentType, key, err)
+	}
+	return document, nil
+}
+
+func (r *PrarthanaDataMongoRepository) contentCollection(contentType string) (*mongo.Collection, error) {
+	switch contentType {
+	case entity.ContentShloks:
+		return r.shlokCollection, nil
+	case entity.ContentStotras:
+		return r.stotraCollection, nil
+	case entity.ContentPrarthanas:
+		return r.prarthanaCollection, nil
+	case entity.ContentDeities:
+		return r.deityCollection, nil
+	}
+	return nil, fmt.Errorf("unknown content type %s", contentType)
+}
+
+func contentFilter(contentType string, query entity.ContentQuery) bson.M {
+	filter := bson.M{}
+	if query.Language != "" {
+		// a language is covered when its text is present and not empty
+		present := bson.M{"$nin": bson.A{nil, ""}}
+		switch contentType {
+		case entity.ContentShloks:
+			filter["shlok."+query.Language] = present
+		case entity.ContentPrarthanas:
+			filter["title."+query.Language] = present
+			filter["description."+query.Language] = present
+		default:
+			filter["title."+query.Language] = present
+		}
+	}
+	if query.Festival != "" {
+		filter["festival_ids"] = query.Festival
+	}
+	if query.Region != "" {
+		filter["region"] = query.Region
+	}
+	if query.IntentBased != nil {
+		filter["intent_based"] = *query.IntentBased
+	}
+	if query.StudioRecorded != nil {
+		filter["audio_info.is_studio_recorded"] = *query.StudioRecorded
+	}
+	return filter
+}
+
+func contentSort(contentType string) bson.D {
+	switch contentType {
+	case entity.ContentShloks, entity.ContentStotras:
+		return bson.D{{Key: "int_id", Value: 1}}
+	case entity.ContentDeities:
+		return bson.D{{Key: "order", Value: 1}, {Key: "_id", Value: 1}}
+	}
+	return bson.D{{Key: "_id", Value: 1}}
+}
+
+func projection(fields []string) bson.M {
+	projection := bson.M{"_id": 1}
+	for _, field := range fields {
+		projection[field] = 1
+	}
+	return projection
+}
+
+// GetSourceHashes returns the stored source hash of every document of a content type, keyed
+// by the id rows are ingested under: _id for shloks and stotras, TmpId for prarthanas and deities.
+func (r *PrarthanaDataMongoRepository) GetSourceHashes(ctx context.Context, contentType string) (map[string]string, error) {
+	collection, err := r.contentCollection(contentType)
+	if err != nil {
+		return nil, err
+	}
+	keyField := "_id"
+	if contentType == entity.ContentPrarthanas || contentType == entity.ContentDeities {
+		keyField = "TmpId"
+	}
+	opts := options.Find().SetProjection(bson.M{keyField: 1, "source_hash": 1})
+	cursor, err := collection.Find(ctx, bson.M{"source_hash": bson.M{"$nin": bson.A{nil, ""}}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching source hashes of %s: %w", contentType, err)
+	}
+	defer cursor.Close(ctx)
+
+	hashes := make(map[string]string)
+	for cursor.Next(ctx) {
+		var document bson.M
+		if err := cursor.Decode(&document); err != nil {
+			return nil, fmt.Errorf("error decoding source hash of %s: %w", contentType, err)
+		}
+		key, _ := document[keyField].(string)
+		hash, _ := document["source_hash"].(string)
+		hashes[key] = hash
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+	return hashes, nil
+}
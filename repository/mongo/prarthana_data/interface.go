@@ -14,6 +14,14 @@ type MongoRepository interface {
 	GetTmpIdToDeityIdMap(ctx context.Context) (map[string]string, error)
 	GetAllStotras(ctx context.Context) (map[string]entity.Stotra, error)
 	GetAllDeities(ctx context.Context) ([]entity.DeityDocument, error)
+	GetAllPrarthanas(ctx context.Context) ([]entity.Prarthana, error)
+	GetPrarthanasByTmpIds(ctx context.Context, tmpIds []string) ([]entity.Prarthana, error)
+	GetDeitiesByTmpIds(ctx context.Context, tmpIds []string) ([]entity.DeityDocument, error)
 	GeneratePrarthanaTmpIdToIdMap(ctx context.Context) (map[string]string, error)
 	GenerateDeityTmpIdToIdMap(ctx context.Context) (map[string]string, error)
+	UpdateDeityPrarthanaLinks(ctx context.Context, deityPrarthanas map[string][]string, prarthanaDeities map[string][]string) error
+	FindContent(ctx context.Context, contentType string, query entity.ContentQuery, fields []string) ([]entity.ContentDocument, int64, error)
+	GetContentByKey(ctx context.Context, contentType string, key string, fields []string) (entity.ContentDocument, error)
+	GetPrarthanaContent(ctx context.Context, key string) (entity.PrarthanaContent, error)
+	GetSourceHashes(ctx context.Context, contentType string) (map[string]string, error)
 }
@@ -0,0 +1,59 @@
+package prarthana_data
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// GetPrarthanaContent looks a prarthana up by _id or TmpId and joins in one aggregation the
+// stotras referenced by any chapter of any variant and the shloks of those stotras.
+func (r *PrarthanaDataMongoRepository) GetPrarthanaContent(ctx context.Context, key string) (entity.PrarthanaContent, error) {
+	// flattens variants[].chapters[].stotra_ids
+	stotraIds := bson.M{"$reduce": bson.M{
+		"input":        bson.M{"$ifNull": bson.A{"$variants", bson.A{}}},
+		"initialValue": bson.A{},
+		"in": bson.M{"$concatArrays": bson.A{"$$value", bson.M{"$reduce": bson.M{
+			"input":        bson.M{"$ifNull": bson.A{"$$this.chapters", bson.A{}}},
+			"initialValue": bson.A{},
+			"in":           bson.M{"$concatArrays": bson.A{"$$value", bson.M{"$ifNull": bson.A{"$$this.stotra_ids", bson.A{}}}}},
+		}}}},
+	}}
+	shlokIds := bson.M{"$reduce": bson.M{
+		"input":        "$stotras",
+		"initialValue": bson.A{},
+		"in":           bson.M{"$concatArrays": bson.A{"$$value", bson.M{"$ifNull": bson.A{"$$this.shlok_ids", bson.A{}}}}},
+	}}
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"$or": bson.A{bson.M{"_id": key}, bson.M{"TmpId": key}}}},
+		bson.M{"$limit": 1},
+		bson.M{"$lookup": bson.M{
+			"from":     stotra_collection,
+			"let":      bson.M{"ids": stotraIds},
+			"pipeline": bson.A{bson.M{"$match": bson.M{"$expr": bson.M{"$in": bson.A{"$_id", "$$ids"}}}}},
+			"as":       "stotras",
+		}},
+		bson.M{"$lookup": bson.M{
+			"from":     shlok_collection,
+			"let":      bson.M{"ids": shlokIds},
+			"pipeline": bson.A{bson.M{"$match": bson.M{"$expr": bson.M{"$in": bson.A{"$_id", "$$ids"}}}}},
+			"as":       "shloks",
+		}},
+	}
+	cursor, err := r.prarthanaCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return entity.PrarthanaContent{}, fmt.Errorf("error resolving prarthana %s: %w", key, err)
+	}
+	defer cursor.Close(ctx)
+
+	var content []entity.PrarthanaContent
+	if err = cursor.All(ctx, &content); err != nil {
+		return entity.PrarthanaContent{}, fmt.Errorf("error decoding prarthana %s: %w", key, err)
+	}
+	if len(content) == 0 {
+		return entity.PrarthanaContent{}, entity.ErrContentNotFound
+	}
+	return content[0], nil
+}
@@ -7,6 +7,8 @@ import (
 	mongoCommons "github.com/Out-Of-India-Theory/oit-go-commons/mongo"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/change_outbox"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -28,6 +30,10 @@ type PrarthanaDataMongoRepository struct {
 	deityCollection     *mongo.Collection
 	shlokCollection     *mongo.Collection
 	stotraCollection    *mongo.Collection
+	mongoClient         *mongo.Client
+	// changeOutbox shares mongoClient so events are appended in the transaction of their write
+	changeOutbox change_outbox.MongoRepository
+	emitChanges  bool
 }
 
 func InitPrarthanaDataMongoRepository(ctx context.Context, config configuration.Configuration) *PrarthanaDataMongoRepository {
@@ -38,6 +44,9 @@ func InitPrarthanaDataMongoRepository(ctx context.Context, config configuration.
 		deityCollection:     mongoClient.Database(config.MongoConfig.Database).Collection(deity_collection),
 		shlokCollection:     mongoClient.Database(config.MongoConfig.Database).Collection(shlok_collection),
 		stotraCollection:    mongoClient.Database(config.MongoConfig.Database).Collection(stotra_collection),
+		mongoClient:         mongoClient,
+		changeOutbox:        change_outbox.InitChangeOutboxMongoRepositoryForDatabase(ctx, mongoClient.Database(config.MongoConfig.Database)),
+		emitChanges:         config.ChangeEventConfig.Enabled,
 	}
 }
 
@@ -48,22 +57,27 @@ func (r *PrarthanaDataMongoRepository) InsertManyShloks(ctx context.Context, shl
 	}
 
 	for _, shlok := range shloks {
-		result := r.shlokCollection.FindOneAndReplace(ctx, bson.M{"_id": shlok.ID}, shlok)
-		if result.Err() != nil {
-			if result.Err() == mongo.ErrNoDocuments {
-				log.Printf("No existing document found for ID: %v. Inserting new shlok.\n", shlok.ID)
-				_, err := r.shlokCollection.InsertOne(ctx, shlok)
-				if err != nil {
-					log.Printf("Failed to insert shlok with ID: %v. Error: %v\n", shlok.ID, err)
-					return fmt.Errorf("failed to insert shlok with ID %v: %w", shlok.ID, err)
+		err := r.writeDocument(ctx, func(ctx context.Context) (entity.DocumentChange, error) {
+			result := r.shlokCollection.FindOneAndReplace(ctx, bson.M{"_id": shlok.ID}, shlok)
+			if result.Err() != nil {
+				if result.Err() == mongo.ErrNoDocuments {
+					log.Printf("No existing document found for ID: %v. Inserting new shlok.\n", shlok.ID)
+					_, err := r.shlokCollection.InsertOne(ctx, shlok)
+					if err != nil {
+						log.Printf("Failed to insert shlok with ID: %v. Error: %v\n", shlok.ID, err)
+						return entity.DocumentChange{}, fmt.Errorf("failed to insert shlok with ID %v: %w", shlok.ID, err)
+					}
+					log.Printf("Successfully inserted new shlok with ID: %v.\n", shlok.ID)
+					return documentChange(shlok_collection, shlok.ID, nil, shlok, true)
 				}
-				log.Printf("Successfully inserted new shlok with ID: %v.\n", shlok.ID)
-			} else {
 				log.Printf("Failed to find and replace shlok with ID: %v. Error: %v\n", shlok.ID, result.Err())
-				return fmt.Errorf("failed to find and replace shlok with ID %v: %w", shlok.ID, result.Err())
+				return entity.DocumentChange{}, fmt.Errorf("failed to find and replace shlok with ID %v: %w", shlok.ID, result.Err())
 			}
-		} else {
 			log.Printf("Successfully updated shlok with ID: %v.\n", shlok.ID)
+			return documentChange(shlok_collection, shlok.ID, recordRaw(result), shlok, true)
+		})
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -76,22 +90,27 @@ func (r *PrarthanaDataMongoRepository) InsertManyStotras(ctx context.Context, st
 	}
 
 	for _, stotra := range stotras {
-		result := r.stotraCollection.FindOneAndReplace(ctx, bson.M{"_id": stotra.ID}, stotra)
-		if result.Err() != nil {
-			if result.Err() == mongo.ErrNoDocuments {
-				log.Printf("No existing document found for ID: %v. Inserting new stotras.\n", stotra.ID)
-				_, err := r.stotraCollection.InsertOne(ctx, stotra)
-				if err != nil {
-					log.Printf("Failed to insert stotras with ID: %v. Error: %v\n", stotra.ID, err)
-					return fmt.Errorf("failed to insert stotras with ID %v: %w", stotra.ID, err)
+		err := r.writeDocument(ctx, func(ctx context.Context) (entity.DocumentChange, error) {
+			result := r.stotraCollection.FindOneAndReplace(ctx, bson.M{"_id": stotra.ID}, stotra)
+			if result.Err() != nil {
+				if result.Err() == mongo.ErrNoDocuments {
+					log.Printf("No existing document found for ID: %v. Inserting new stotras.\n", stotra.ID)
+					_, err := r.stotraCollection.InsertOne(ctx, stotra)
+					if err != nil {
+						log.Printf("Failed to insert stotras with ID: %v. Error: %v\n", stotra.ID, err)
+						return entity.DocumentChange{}, fmt.Errorf("failed to insert stotras with ID %v: %w", stotra.ID, err)
+					}
+					log.Printf("Successfully inserted new stotras with ID: %v.\n", stotra.ID)
+					return documentChange(stotra_collection, stotra.ID, nil, stotra, true)
 				}
-				log.Printf("Successfully inserted new stotras with ID: %v.\n", stotra.ID)
-			} else {
 				log.Printf("Failed to find and replace stotras with ID: %v. Error: %v\n", stotra.ID, result.Err())
-				return fmt.Errorf("failed to find and replace stotras with ID %v: %w", stotra.ID, result.Err())
+				return entity.DocumentChange{}, fmt.Errorf("failed to find and replace stotras with ID %v: %w", stotra.ID, result.Err())
 			}
-		} else {
 			log.Printf("Successfully updated stotras with ID: %v.\n", stotra.ID)
+			return documentChange(stotra_collection, stotra.ID, recordRaw(result), stotra, true)
+		})
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -104,58 +123,70 @@ func (r *PrarthanaDataMongoRepository) InsertManyDeities(ctx context.Context, de
 	}
 
 	for _, deity := range deities {
-		result := r.deityCollection.FindOne(ctx, bson.M{"TmpId": deity.TmpId})
-		if result.Err() != nil {
-			if result.Err() == mongo.ErrNoDocuments {
-				log.Printf("No existing document found for ID: %v. Inserting new deity.\n", deity.Id)
-				deity.Id = uuid.NewString()
-				_, err := r.deityCollection.InsertOne(ctx, deity)
-				if err != nil {
-					log.Printf("Failed to insert deity with ID: %v. Error: %v\n", deity.Id, err)
-					return fmt.Errorf("failed to insert deity with ID %v: %w", deity.Id, err)
-				}
-				log.Printf("Successfully inserted new deity with ID: %v.\n", deity.Id)
-			} else {
-				log.Printf("Failed to find and replace deity with ID: %v. Error: %v\n", deity.Id, result.Err())
-				return fmt.Errorf("failed to find and replace deity with ID %v: %w", deity.Id, result.Err())
-			}
-		} else {
-			var doc entity.DeityDocument
-			err := result.Decode(&doc)
-			if err != nil {
-				return fmt.Errorf("error decoding deity document: %w", err)
-			}
-			deity.Id = doc.Id
-			// Convert the deity struct to a BSON map
-			updateData, err := bson.Marshal(deity)
-			if err != nil {
-				return fmt.Errorf("error marshalling deity data: %w", err)
-			}
-			var updateDoc bson.M
-			err = bson.Unmarshal(updateData, &updateDoc)
-			if err != nil {
-				return fmt.Errorf("error unmarshalling deity data to bson.M: %w", err)
-			}
+		err := r.writeDocument(ctx, func(ctx context.Context) (entity.DocumentChange, error) {
+			return r.upsertDeity(ctx, deity)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			updateResult, err := r.deityCollection.UpdateOne(
-				ctx,
-				bson.M{"TmpId": deity.TmpId},
-				bson.M{"$set": updateDoc},
-			)
+// upsertDeity inserts the deity or $sets its fields on the stored one with the same TmpId.
+func (r *PrarthanaDataMongoRepository) upsertDeity(ctx context.Context, deity entity.DeityDocument) (entity.DocumentChange, error) {
+	result := r.deityCollection.FindOne(ctx, bson.M{"TmpId": deity.TmpId})
+	if result.Err() != nil {
+		if result.Err() == mongo.ErrNoDocuments {
+			log.Printf("No existing document found for ID: %v. Inserting new deity.\n", deity.Id)
+			deity.Id = uuid.NewString()
+			_, err := r.deityCollection.InsertOne(ctx, deity)
 			if err != nil {
-				return fmt.Errorf("error updating deity with ID %v: %w", deity.Id, err)
-			}
-			//updateResult, err := r.deityCollection.ReplaceOne(ctx, bson.M{"TmpId": deity.TmpId}, deity)
-			//if err != nil {
-			//	return fmt.Errorf("error updating deity with ID %v: %w", deity.Id, err)
-			//}
-			if updateResult.MatchedCount == 0 {
-				return fmt.Errorf("error updating deity with ID %v: %w", deity.Id, err)
+				log.Printf("Failed to insert deity with ID: %v. Error: %v\n", deity.Id, err)
+				return entity.DocumentChange{}, fmt.Errorf("failed to insert deity with ID %v: %w", deity.Id, err)
 			}
-			log.Printf("Successfully updated deity with ID: %v.\n", deity.Id)
+			log.Printf("Successfully inserted new deity with ID: %v.\n", deity.Id)
+			return documentChange(deity_collection, deity.Id, nil, deity, false)
+		} else {
+			log.Printf("Failed to find and replace deity with ID: %v. Error: %v\n", deity.Id, result.Err())
+			return entity.DocumentChange{}, fmt.Errorf("failed to find and replace deity with ID %v: %w", deity.Id, result.Err())
+		}
+	} else {
+		var doc entity.DeityDocument
+		err := result.Decode(&doc)
+		if err != nil {
+			return entity.DocumentChange{}, fmt.Errorf("error decoding deity document: %w", err)
+		}
+		deity.Id = doc.Id
+		// Convert the deity struct to a BSON map
+		updateData, err := bson.Marshal(deity)
+		if err != nil {
+			return entity.DocumentChange{}, fmt.Errorf("error marshalling deity data: %w", err)
+		}
+		var updateDoc bson.M
+		err = bson.Unmarshal(updateData, &updateDoc)
+		if err != nil {
+			return entity.DocumentChange{}, fmt.Errorf("error unmarshalling deity data to bson.M: %w", err)
+		}
+
+		updateResult, err := r.deityCollection.UpdateOne(
+			ctx,
+			bson.M{"TmpId": deity.TmpId},
+			bson.M{"$set": updateDoc},
+		)
+		if err != nil {
+			return entity.DocumentChange{}, fmt.Errorf("error updating deity with ID %v: %w", deity.Id, err)
+		}
+		//updateResult, err := r.deityCollection.ReplaceOne(ctx, bson.M{"TmpId": deity.TmpId}, deity)
+		//if err != nil {
+		//	return entity.DocumentChange{}, fmt.Errorf("error updating deity with ID %v: %w", deity.Id, err)
+		//}
+		if updateResult.MatchedCount == 0 {
+			return entity.DocumentChange{}, fmt.Errorf("error updating deity with ID %v: %w", deity.Id, err)
 		}
+		log.Printf("Successfully updated deity with ID: %v.\n", deity.Id)
+		return documentChange(deity_collection, deity.Id, recordRaw(result), updateDoc, false)
 	}
-	return nil
 }
 
 func (r *PrarthanaDataMongoRepository) InsertManyPrarthanas(ctx context.Context, prarthanas []entity.Prarthana) error {
@@ -165,56 +196,11 @@ func (r *PrarthanaDataMongoRepository) InsertManyPrarthanas(ctx context.Context,
 	}
 
 	for _, prarthana := range prarthanas {
-		result := r.prarthanaCollection.FindOne(ctx, bson.M{"TmpId": prarthana.TmpId})
-		if result.Err() != nil {
-			if result.Err() == mongo.ErrNoDocuments {
-				log.Printf("No existing document found for ID: %v. Inserting new prarthana.\n", prarthana.Id)
-				prarthana.Id = uuid.NewString()
-				_, err := r.prarthanaCollection.InsertOne(ctx, prarthana)
-				if err != nil {
-					log.Printf("Failed to insert prarthana with ID: %v. Error: %v\n", prarthana.Id, err)
-					return fmt.Errorf("failed to insert prarthana with ID %v: %w", prarthana.Id, err)
-				}
-				log.Printf("Successfully inserted new prarthana with ID: %v.\n", prarthana.Id)
-			} else {
-				log.Printf("Failed to find and replace prarthana with ID: %v. Error: %v\n", prarthana.Id, result.Err())
-				return fmt.Errorf("failed to find and replace prarthana with ID %v: %w", prarthana.Id, result.Err())
-			}
-		} else {
-			var prarthanaDoc entity.Prarthana
-			err := result.Decode(&prarthanaDoc)
-			if err != nil {
-				return fmt.Errorf("error decoding prarthana document: %w", err)
-			}
-			prarthana.Id = prarthanaDoc.Id
-
-			updateData, err := bson.Marshal(prarthana)
-			if err != nil {
-				return fmt.Errorf("error marshalling prarthana data: %w", err)
-			}
-			var updateDoc bson.M
-			err = bson.Unmarshal(updateData, &updateDoc)
-			if err != nil {
-				return fmt.Errorf("error unmarshalling deity data to bson.M: %w", err)
-			}
-
-			updateResult, err := r.prarthanaCollection.UpdateOne(
-				ctx,
-				bson.M{"TmpId": prarthana.TmpId},
-				bson.M{"$set": updateDoc},
-			)
-			if err != nil {
-				return fmt.Errorf("error updating prarthana with ID %v: %w", prarthana.Id, err)
-			}
-
-			//updateResult, err := r.prarthanaCollection.ReplaceOne(ctx, bson.M{"TmpId": prarthana.TmpId}, prarthana)
-			//if err != nil {
-			//	return fmt.Errorf("error updating prarthana with ID %v: %w", prarthana.Id, err)
-			//}
-			if updateResult.MatchedCount == 0 {
-				return fmt.Errorf("error updating prarthana with ID %v: %w", prarthana.Id, err)
-			}
-			log.Printf("Successfully updated prarthana with ID: %v.\n", prarthana.Id)
+		err := r.writeDocument(ctx, func(ctx context.Context) (entity.DocumentChange, error) {
+			return r.upsertPrarthana(ctx, prarthana)
+		})
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -233,6 +219,64 @@ func (r *PrarthanaDataMongoRepository) InsertManyPrarthanas(ctx context.Context,
 	//return nil
 }
 
+// upsertPrarthana inserts the prarthana or $sets its fields on the stored one with the same
+// TmpId.
+func (r *PrarthanaDataMongoRepository) upsertPrarthana(ctx context.Context, prarthana entity.Prarthana) (entity.DocumentChange, error) {
+	result := r.prarthanaCollection.FindOne(ctx, bson.M{"TmpId": prarthana.TmpId})
+	if result.Err() != nil {
+		if result.Err() == mongo.ErrNoDocuments {
+			log.Printf("No existing document found for ID: %v. Inserting new prarthana.\n", prarthana.Id)
+			prarthana.Id = uuid.NewString()
+			_, err := r.prarthanaCollection.InsertOne(ctx, prarthana)
+			if err != nil {
+				log.Printf("Failed to insert prarthana with ID: %v. Error: %v\n", prarthana.Id, err)
+				return entity.DocumentChange{}, fmt.Errorf("failed to insert prarthana with ID %v: %w", prarthana.Id, err)
+			}
+			log.Printf("Successfully inserted new prarthana with ID: %v.\n", prarthana.Id)
+			return documentChange(prarthana_collection, prarthana.Id, nil, prarthana, false)
+		} else {
+			log.Printf("Failed to find and replace prarthana with ID: %v. Error: %v\n", prarthana.Id, result.Err())
+			return entity.DocumentChange{}, fmt.Errorf("failed to find and replace prarthana with ID %v: %w", prarthana.Id, result.Err())
+		}
+	} else {
+		var prarthanaDoc entity.Prarthana
+		err := result.Decode(&prarthanaDoc)
+		if err != nil {
+			return entity.DocumentChange{}, fmt.Errorf("error decoding prarthana document: %w", err)
+		}
+		prarthana.Id = prarthanaDoc.Id
+
+		updateData, err := bson.Marshal(prarthana)
+		if err != nil {
+			return entity.DocumentChange{}, fmt.Errorf("error marshalling prarthana data: %w", err)
+		}
+		var updateDoc bson.M
+		err = bson.Unmarshal(updateData, &updateDoc)
+		if err != nil {
+			return entity.DocumentChange{}, fmt.Errorf("error unmarshalling deity data to bson.M: %w", err)
+		}
+
+		updateResult, err := r.prarthanaCollection.UpdateOne(
+			ctx,
+			bson.M{"TmpId": prarthana.TmpId},
+			bson.M{"$set": updateDoc},
+		)
+		if err != nil {
+			return entity.DocumentChange{}, fmt.Errorf("error updating prarthana with ID %v: %w", prarthana.Id, err)
+		}
+
+		//updateResult, err := r.prarthanaCollection.ReplaceOne(ctx, bson.M{"TmpId": prarthana.TmpId}, prarthana)
+		//if err != nil {
+		//	return entity.DocumentChange{}, fmt.Errorf("error updating prarthana with ID %v: %w", prarthana.Id, err)
+		//}
+		if updateResult.MatchedCount == 0 {
+			return entity.DocumentChange{}, fmt.Errorf("error updating prarthana with ID %v: %w", prarthana.Id, err)
+		}
+		log.Printf("Successfully updated prarthana with ID: %v.\n", prarthana.Id)
+		return documentChange(prarthana_collection, prarthana.Id, recordRaw(result), updateDoc, false)
+	}
+}
+
 func (r *PrarthanaDataMongoRepository) GetTmpIdToPrarthanaIds(ctx context.Context) (map[string]string, map[string]string, error) {
 	filter := bson.M{}
 	projection := bson.M{
@@ -340,6 +384,43 @@ func (r *PrarthanaDataMongoRepository) GetAllDeities(ctx context.Context) ([]ent
 	return deities, nil
 }
 
+func (r *PrarthanaDataMongoRepository) GetAllPrarthanas(ctx context.Context) ([]entity.Prarthana, error) {
+	return r.GetPrarthanasByTmpIds(ctx, nil)
+}
+
+// GetPrarthanasByTmpIds returns the prarthanas with the given TmpIds, or all of them when tmpIds is nil.
+func (r *PrarthanaDataMongoRepository) GetPrarthanasByTmpIds(ctx context.Context, tmpIds []string) ([]entity.Prarthana, error) {
+	filter := bson.M{}
+	if tmpIds != nil {
+		filter["TmpId"] = bson.M{"$in": tmpIds}
+	}
+	cursor, err := r.prarthanaCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching prarthanas: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var prarthanas []entity.Prarthana
+	if err = cursor.All(ctx, &prarthanas); err != nil {
+		return nil, fmt.Errorf("error decoding prarthanas: %w", err)
+	}
+	return prarthanas, nil
+}
+
+func (r *PrarthanaDataMongoRepository) GetDeitiesByTmpIds(ctx context.Context, tmpIds []string) ([]entity.DeityDocument, error) {
+	cursor, err := r.deityCollection.Find(ctx, bson.M{"TmpId": bson.M{"$in": tmpIds}})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching deities: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var deities []entity.DeityDocument
+	if err = cursor.All(ctx, &deities); err != nil {
+		return nil, fmt.Errorf("error decoding deities: %w", err)
+	}
+	return deities, nil
+}
+
 func (r *PrarthanaDataMongoRepository) GeneratePrarthanaTmpIdToIdMap(ctx context.Context) (map[string]string, error) {
 	// Define the map to store the TmpId -> _id mapping
 	tmpIdToIdMap := make(map[string]string)
@@ -395,3 +476,89 @@ func (r *PrarthanaDataMongoRepository) GenerateDeityTmpIdToIdMap(ctx context.Con
 	}
 	return tmpIdToIdMap, nil
 }
+
+// UpdateDeityPrarthanaLinks sets deities.prarthanas and prarthanas.deity_ids from the given
+// _id keyed maps and empties the links of every document not present in them. Documents whose
+// links changed are recorded like any other write.
+func (r *PrarthanaDataMongoRepository) UpdateDeityPrarthanaLinks(ctx context.Context, deityPrarthanas map[string][]string, prarthanaDeities map[string][]string) error {
+	if err := r.updateLinks(ctx, r.deityCollection, "prarthanas", deityPrarthanas); err != nil {
+		return fmt.Errorf("error updating deity prarthanas: %w", err)
+	}
+	if err := r.updateLinks(ctx, r.prarthanaCollection, "deity_ids", prarthanaDeities); err != nil {
+		return fmt.Errorf("error updating prarthana deity ids: %w", err)
+	}
+	return nil
+}
+
+// updateLinks only writes the documents whose links differ from the stored ones, in one
+// transaction with their change events. Documents without the field get an empty list
+// without an event.
+func (r *PrarthanaDataMongoRepository) updateLinks(ctx context.Context, collection *mongo.Collection, field string, links map[string][]string) error {
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"_id": 1, field: 1}))
+	if err != nil {
+		return fmt.Errorf("error fetching %s links: %w", collection.Name(), err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []mongo.WriteModel
+	var changes []entity.DocumentChange
+	for cursor.Next(ctx) {
+		var document bson.M
+		if err := cursor.Decode(&document); err != nil {
+			return fmt.Errorf("error decoding %s links: %w", collection.Name(), err)
+		}
+		id, _ := document["_id"].(string)
+		linkedIds := links[id]
+		if linkedIds == nil {
+			linkedIds = []string{}
+		}
+		stored, ok := document[field].(bson.A)
+		if ok && sameIds(stored, linkedIds) {
+			continue
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$set": bson.M{field: linkedIds}}))
+		if ok || len(linkedIds) > 0 {
+			changes = append(changes, entity.DocumentChange{Collection: collection.Name(), Id: id, Action: entity.ChangeUpdated, Fields: []string{field}})
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("cursor error: %w", err)
+	}
+	if len(models) == 0 {
+		return nil
+	}
+	err = r.inTransaction(ctx, func(ctx context.Context) error {
+		if _, err := collection.BulkWrite(ctx, models); err != nil {
+			return err
+		}
+		for _, change := range changes {
+			if err := r.appendChangeEvent(ctx, change); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		util.RecordChange(ctx, change.Collection, change.Id, change.Action, change.Fields)
+	}
+	return nil
+}
+
+// sameIds reports whether the stored links are ids in the same order, which is the display
+// order of a deity's prarthanas.
+func sameIds(stored bson.A, ids []string) bool {
+	if len(stored) != len(ids) {
+		return false
+	}
+	for i, id := range ids {
+		if storedId, ok := stored[i].(string); !ok || storedId != id {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,11 @@
+package schema_migration
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type MongoRepository interface {
+	Migrate(ctx context.Context) ([]entity.SchemaMigration, error)
+	GetAppliedMigrations(ctx context.Context) ([]entity.SchemaMigration, error)
+}
@@ -0,0 +1,192 @@
+package schema_migration
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const (
+	prarthana_collection = "prarthanas"
+	deity_collection     = "deities"
+	shlok_collection     = "shloks"
+	stotra_collection    = "stotras"
+	audit_collection     = "ingestion_audit"
+	snapshot_collection  = "sheet_snapshots"
+	outbox_collection    = "change_outbox"
+	cdn_collection       = "cdn_invalidations"
+)
+
+// migration is a single schema change. Versions are applied in ascending order
+// and every up func must be safe to re-run, since two instances starting at the
+// same time can both apply a version before either records it.
+type migration struct {
+	version     int
+	description string
+	up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// migrations must only ever be appended to; never renumber or edit an applied one.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "unique TmpId indexes on prarthanas and deities",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			if err := createUniqueIndex(ctx, db.Collection(prarthana_collection), "TmpId", nonEmptyString, "TmpId_unique"); err != nil {
+				return err
+			}
+			return createUniqueIndex(ctx, db.Collection(deity_collection), "TmpId", nonEmptyString, "TmpId_unique")
+		},
+	},
+	{
+		version:     2,
+		description: "unique slug index on deities",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			return createUniqueIndex(ctx, db.Collection(deity_collection), "slug", nonEmptyString, "slug_unique")
+		},
+	},
+	{
+		version:     3,
+		description: "unique int_id indexes on shloks and stotras",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			if err := createUniqueIndex(ctx, db.Collection(shlok_collection), "int_id", positiveNumber, "int_id_unique"); err != nil {
+				return err
+			}
+			return createUniqueIndex(ctx, db.Collection(stotra_collection), "int_id", positiveNumber, "int_id_unique")
+		},
+	},
+	{
+		version:     4,
+		description: "rename prarthana ui_info.templatenumber to ui_info.template_number",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			return renameField(ctx, db.Collection(prarthana_collection), "ui_info.templatenumber", "ui_info.template_number")
+		},
+	},
+	{
+		version:     5,
+		description: "backfill empty deity_ids on prarthanas and search_keywords on deities",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			if err := backfillField(ctx, db.Collection(prarthana_collection), "deity_ids", bson.A{}); err != nil {
+				return err
+			}
+			return backfillField(ctx, db.Collection(deity_collection), "search_keywords", bson.A{})
+		},
+	},
+	{
+		version:     6,
+		description: "ingestion_audit indexes on started_at and actor.subject",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			if err := createIndex(ctx, db.Collection(audit_collection), bson.D{{Key: "started_at", Value: -1}}, "started_at"); err != nil {
+				return err
+			}
+			return createIndex(ctx, db.Collection(audit_collection), bson.D{{Key: "actor.subject", Value: 1}, {Key: "started_at", Value: -1}}, "actor_subject_started_at")
+		},
+	},
+	{
+		version:     7,
+		description: "sheet_snapshots index on sheet_id, worksheet and fetched_at",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndex(ctx, db.Collection(snapshot_collection), bson.D{{Key: "sheet_id", Value: 1}, {Key: "worksheet", Value: 1}, {Key: "fetched_at", Value: -1}}, "sheet_id_worksheet_fetched_at")
+		},
+	},
+	{
+		version:     8,
+		description: "change_outbox indexes on status and occurred_at, expiry of published events after 30 days",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			if err := createIndex(ctx, db.Collection(outbox_collection), bson.D{{Key: "status", Value: 1}, {Key: "occurred_at", Value: 1}, {Key: "version", Value: 1}}, "status_occurred_at_version"); err != nil {
+				return err
+			}
+			if err := createIndex(ctx, db.Collection(outbox_collection), bson.D{{Key: "content_type", Value: 1}, {Key: "document_id", Value: 1}, {Key: "occurred_at", Value: -1}}, "content_type_document_id_occurred_at"); err != nil {
+				return err
+			}
+			return createTTLIndex(ctx, db.Collection(outbox_collection), "published_at", 30*24*time.Hour, "published_at_ttl")
+		},
+	},
+	{
+		version:     9,
+		description: "cdn_invalidations indexes on created_at, status, run_id and paths",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			if err := createIndex(ctx, db.Collection(cdn_collection), bson.D{{Key: "created_at", Value: -1}}, "created_at"); err != nil {
+				return err
+			}
+			if err := createIndex(ctx, db.Collection(cdn_collection), bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}, "status_created_at"); err != nil {
+				return err
+			}
+			if err := createIndex(ctx, db.Collection(cdn_collection), bson.D{{Key: "run_id", Value: 1}}, "run_id"); err != nil {
+				return err
+			}
+			return createIndex(ctx, db.Collection(cdn_collection), bson.D{{Key: "paths", Value: 1}, {Key: "created_at", Value: -1}}, "paths_created_at")
+		},
+	},
+}
+
+// The fields have no omitempty, so every document carries them; the partial filters leave
+// out the zero values older documents may share, which would otherwise fail the index.
+var (
+	nonEmptyString = bson.M{"$type": "string", "$gt": ""}
+	positiveNumber = bson.M{"$type": "number", "$gt": 0}
+)
+
+// createUniqueIndex makes field unique among the documents whose value matches filter.
+func createUniqueIndex(ctx context.Context, collection *mongo.Collection, field string, filter bson.M, name string) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: field, Value: 1}},
+		Options: options.Index().
+			SetName(name).
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{field: filter}),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating index %s on %s: %w", name, collection.Name(), err)
+	}
+	return nil
+}
+
+func createIndex(ctx context.Context, collection *mongo.Collection, keys bson.D, name string) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetName(name),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating index %s on %s: %w", name, collection.Name(), err)
+	}
+	return nil
+}
+
+// createTTLIndex expires documents ttl after the date in field; documents without it are kept.
+func createTTLIndex(ctx context.Context, collection *mongo.Collection, field string, ttl time.Duration, name string) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: 1}},
+		Options: options.Index().SetName(name).SetExpireAfterSeconds(int32(ttl.Seconds())),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating index %s on %s: %w", name, collection.Name(), err)
+	}
+	return nil
+}
+
+func renameField(ctx context.Context, collection *mongo.Collection, from, to string) error {
+	_, err := collection.UpdateMany(ctx,
+		bson.M{from: bson.M{"$exists": true}},
+		bson.M{"$rename": bson.M{from: to}},
+	)
+	if err != nil {
+		return fmt.Errorf("error renaming %s to %s on %s: %w", from, to, collection.Name(), err)
+	}
+	return nil
+}
+
+// backfillField sets field to value on documents where it is missing or null.
+func backfillField(ctx context.Context, collection *mongo.Collection, field string, value interface{}) error {
+	_, err := collection.UpdateMany(ctx,
+		bson.M{field: nil},
+		bson.M{"$set": bson.M{field: value}},
+	)
+	if err != nil {
+		return fmt.Errorf("error backfilling %s on %s: %w", field, collection.Name(), err)
+	}
+	return nil
+}
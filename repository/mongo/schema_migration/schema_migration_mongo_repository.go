@@ -0,0 +1,83 @@
+package schema_migration
+
+import (
+	"context"
+	"fmt"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	mongoCommons "github.com/Out-Of-India-Theory/oit-go-commons/mongo"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"log"
+	"time"
+)
+
+const schema_migration_collection = "schema_migrations"
+
+type SchemaMigrationMongoRepository struct {
+	logger              *zap.Logger
+	database            *mongo.Database
+	migrationCollection *mongo.Collection
+}
+
+func InitSchemaMigrationMongoRepository(ctx context.Context, config configuration.Configuration) *SchemaMigrationMongoRepository {
+	mongoClient := mongoCommons.InitMongoClient(ctx, config.MongoConfig)
+	database := mongoClient.Database(config.MongoConfig.Database)
+	return &SchemaMigrationMongoRepository{
+		logger:              logging.WithContext(ctx),
+		database:            database,
+		migrationCollection: database.Collection(schema_migration_collection),
+	}
+}
+
+// Migrate applies every pending migration in version order and returns the ones applied by this call.
+// It stops at the first failure so later migrations never run against a partially migrated schema.
+func (r *SchemaMigrationMongoRepository) Migrate(ctx context.Context) ([]entity.SchemaMigration, error) {
+	applied, err := r.GetAppliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	appliedVersions := make(map[int]bool)
+	for _, m := range applied {
+		appliedVersions[m.Version] = true
+	}
+
+	var newlyApplied []entity.SchemaMigration
+	for _, m := range migrations {
+		if appliedVersions[m.version] {
+			continue
+		}
+		log.Printf("Applying schema migration %d: %s\n", m.version, m.description)
+		if err := m.up(ctx, r.database); err != nil {
+			return newlyApplied, fmt.Errorf("schema migration %d failed: %w", m.version, err)
+		}
+		record := entity.SchemaMigration{
+			Version:     m.version,
+			Description: m.description,
+			AppliedAt:   time.Now().UTC(),
+		}
+		_, err := r.migrationCollection.InsertOne(ctx, record)
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return newlyApplied, fmt.Errorf("error recording schema migration %d: %w", m.version, err)
+		}
+		newlyApplied = append(newlyApplied, record)
+	}
+	return newlyApplied, nil
+}
+
+func (r *SchemaMigrationMongoRepository) GetAppliedMigrations(ctx context.Context) ([]entity.SchemaMigration, error) {
+	cursor, err := r.migrationCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching schema migrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var applied []entity.SchemaMigration
+	if err = cursor.All(ctx, &applied); err != nil {
+		return nil, fmt.Errorf("error decoding schema migrations: %w", err)
+	}
+	return applied, nil
+}
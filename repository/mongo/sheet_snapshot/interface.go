@@ -0,0 +1,13 @@
+package sheet_snapshot
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type MongoRepository interface {
+	Insert(ctx context.Context, snapshot entity.SheetSnapshot) error
+	GetById(ctx context.Context, id string) (entity.SheetSnapshot, error)
+	GetLatest(ctx context.Context, sheetId string, worksheet string) (entity.SheetSnapshot, error)
+	Find(ctx context.Context, query entity.SheetSnapshotQuery) ([]entity.SheetSnapshot, int64, error)
+}
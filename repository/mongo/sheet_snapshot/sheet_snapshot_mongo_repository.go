@@ -0,0 +1,94 @@
+package sheet_snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	mongoCommons "github.com/Out-Of-India-Theory/oit-go-commons/mongo"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const sheet_snapshot_collection = "sheet_snapshots"
+
+type SheetSnapshotMongoRepository struct {
+	logger             *zap.Logger
+	snapshotCollection *mongo.Collection
+}
+
+func InitSheetSnapshotMongoRepository(ctx context.Context, config configuration.Configuration) *SheetSnapshotMongoRepository {
+	mongoClient := mongoCommons.InitMongoClient(ctx, config.MongoConfig)
+	return &SheetSnapshotMongoRepository{
+		logger:             logging.WithContext(ctx),
+		snapshotCollection: mongoClient.Database(config.MongoConfig.Database).Collection(sheet_snapshot_collection),
+	}
+}
+
+func (r *SheetSnapshotMongoRepository) Insert(ctx context.Context, snapshot entity.SheetSnapshot) error {
+	if _, err := r.snapshotCollection.InsertOne(ctx, snapshot); err != nil {
+		return fmt.Errorf("error inserting sheet snapshot of %s: %w", snapshot.Worksheet, err)
+	}
+	return nil
+}
+
+// GetById returns the snapshot with its data.
+func (r *SheetSnapshotMongoRepository) GetById(ctx context.Context, id string) (entity.SheetSnapshot, error) {
+	var snapshot entity.SheetSnapshot
+	err := r.snapshotCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&snapshot)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return entity.SheetSnapshot{}, entity.ErrSnapshotNotFound
+	}
+	if err != nil {
+		return entity.SheetSnapshot{}, fmt.Errorf("error fetching sheet snapshot %s: %w", id, err)
+	}
+	return snapshot, nil
+}
+
+// GetLatest returns the most recent snapshot of the worksheet, without its data.
+func (r *SheetSnapshotMongoRepository) GetLatest(ctx context.Context, sheetId string, worksheet string) (entity.SheetSnapshot, error) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "fetched_at", Value: -1}}).
+		SetProjection(bson.M{"data": 0})
+	var snapshot entity.SheetSnapshot
+	err := r.snapshotCollection.FindOne(ctx, bson.M{"sheet_id": sheetId, "worksheet": worksheet}, opts).Decode(&snapshot)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return entity.SheetSnapshot{}, entity.ErrSnapshotNotFound
+	}
+	if err != nil {
+		return entity.SheetSnapshot{}, fmt.Errorf("error fetching latest sheet snapshot of %s: %w", worksheet, err)
+	}
+	return snapshot, nil
+}
+
+// Find returns one page of snapshots, newest first, without their data.
+func (r *SheetSnapshotMongoRepository) Find(ctx context.Context, query entity.SheetSnapshotQuery) ([]entity.SheetSnapshot, int64, error) {
+	filter := bson.M{}
+	if query.Worksheet != "" {
+		filter["worksheet"] = query.Worksheet
+	}
+	total, err := r.snapshotCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting sheet snapshots: %w", err)
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "fetched_at", Value: -1}}).
+		SetProjection(bson.M{"data": 0}).
+		SetSkip(int64((query.Page - 1) * query.PageSize)).
+		SetLimit(int64(query.PageSize))
+	cursor, err := r.snapshotCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching sheet snapshots: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	snapshots := []entity.SheetSnapshot{}
+	if err = cursor.All(ctx, &snapshots); err != nil {
+		return nil, 0, fmt.Errorf("error decoding sheet snapshots: %w", err)
+	}
+	return snapshots, total, nil
+}
@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+type Storage interface {
+	Put(ctx context.Context, key string, contentType string, body io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	List(ctx context.Context, prefix string) ([]string, error)
+}
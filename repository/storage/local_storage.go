@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+	"go.uber.org/zap"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage keeps assets on the local filesystem under Root. It stands in for S3 in
+// development, with the files served by the ingestion server itself.
+type LocalStorage struct {
+	logger        *zap.Logger
+	root          string
+	trackReplaced bool
+}
+
+func InitLocalStorage(ctx context.Context, config configuration.Configuration) *LocalStorage {
+	return &LocalStorage{
+		logger:        logging.WithContext(ctx),
+		root:          config.StorageConfig.LocalRoot,
+		trackReplaced: config.CdnConfig.Enabled,
+	}
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, contentType string, body io.Reader, size int64) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	replaced := replacesObject(ctx, s, s.trackReplaced, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating directory for %s: %w", key, err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", key, err)
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, body); err != nil {
+		return fmt.Errorf("error writing %s: %w", key, err)
+	}
+	if replaced {
+		util.RecordChangedAsset(ctx, key)
+	}
+	return nil
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %w", key, err)
+	}
+	return file, nil
+}
+
+func (s *LocalStorage) Exists(ctx context.Context, key string) (bool, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *LocalStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s: %w", prefix, err)
+	}
+	return keys, nil
+}
+
+// path maps a key into the root, refusing keys that would escape it.
+func (s *LocalStorage) path(key string) (string, error) {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("invalid storage key: %s", key)
+	}
+	return path, nil
+}
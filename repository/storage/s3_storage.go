@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"go.uber.org/zap"
+	"io"
+)
+
+// S3Storage talks to S3 or any S3-compatible store (MinIO, localstack) when Endpoint is set.
+type S3Storage struct {
+	logger        *zap.Logger
+	client        *s3.Client
+	bucket        string
+	trackReplaced bool
+}
+
+func InitS3Storage(ctx context.Context, config configuration.Configuration) (*S3Storage, error) {
+	storageConfig := config.StorageConfig
+	var loadOptions []func(*awsConfig.LoadOptions) error
+	loadOptions = append(loadOptions, awsConfig.WithRegion(storageConfig.Region))
+	if storageConfig.AccessKeyId != "" {
+		loadOptions = append(loadOptions, awsConfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(storageConfig.AccessKeyId, storageConfig.SecretAccessKey, "")))
+	}
+	awsCfg, err := awsConfig.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("error loading aws config: %w", err)
+	}
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if storageConfig.Endpoint != "" {
+			o.BaseEndpoint = aws.String(storageConfig.Endpoint)
+		}
+		o.UsePathStyle = storageConfig.UsePathStyle
+	})
+	return &S3Storage{
+		logger:        logging.WithContext(ctx),
+		client:        client,
+		bucket:        storageConfig.Bucket,
+		trackReplaced: config.CdnConfig.Enabled,
+	}, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, contentType string, body io.Reader, size int64) error {
+	replaced := replacesObject(ctx, s, s.trackReplaced, key)
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          body,
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	})
+	if err != nil {
+		return fmt.Errorf("error uploading %s: %w", key, err)
+	}
+	if replaced {
+		util.RecordChangedAsset(ctx, key)
+	}
+	return nil
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error downloading %s: %w", key, err)
+	}
+	return output.Body, nil
+}
+
+func (s *S3Storage) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err == nil {
+		return true, nil
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NotFound" || apiErr.ErrorCode() == "NoSuchKey") {
+		return false, nil
+	}
+	return false, fmt.Errorf("error checking %s: %w", key, err)
+}
+
+func (s *S3Storage) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing %s: %w", prefix, err)
+		}
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+	"io"
+	"log"
+)
+
+const (
+	s3_backend    = "s3"
+	local_backend = "local"
+)
+
+// InitStorage returns the backend selected by StorageConfig.Backend.
+func InitStorage(ctx context.Context, config configuration.Configuration) (Storage, error) {
+	switch config.StorageConfig.Backend {
+	case s3_backend:
+		return InitS3Storage(ctx, config)
+	case local_backend, "":
+		return InitLocalStorage(ctx, config), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", config.StorageConfig.Backend)
+	}
+}
+
+// WithPutListener calls listener with the key of every object stored through the storage,
+// e.g. to drop cached state about the previous object under the key.
+func WithPutListener(storage Storage, listener func(key string)) Storage {
+	return &listenedStorage{Storage: storage, listener: listener}
+}
+
+type listenedStorage struct {
+	Storage
+	listener func(key string)
+}
+
+func (s *listenedStorage) Put(ctx context.Context, key string, contentType string, body io.Reader, size int64) error {
+	if err := s.Storage.Put(ctx, key, contentType, body, size); err != nil {
+		return err
+	}
+	s.listener(key)
+	return nil
+}
+
+// replacesObject reports whether writing key overwrites an object the CDN may still serve
+// from its cache. It is only checked when invalidation is on and the write is made under an
+// audit trail, which collects the keys to invalidate.
+func replacesObject(ctx context.Context, storage Storage, trackReplaced bool, key string) bool {
+	if !trackReplaced || util.GetAuditTrailFromContext(ctx) == nil {
+		return false
+	}
+	exists, err := storage.Exists(ctx, key)
+	if err != nil {
+		// invalidating a new key is harmless, serving a stale one is not
+		log.Printf("Error checking whether %s exists, treating it as replaced: %v\n", key, err)
+		return true
+	}
+	return exists
+}
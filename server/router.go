@@ -2,32 +2,62 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"github.com/Out-Of-India-Theory/oit-go-commons/app"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/controller/ingestion"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/middleware"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/auth"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/facade"
-	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/zoho"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"time"
 )
 
 func registerRoutes(ctx context.Context, app *app.App, service facade.Service, configuration *configuration.Configuration) {
 	basePath := app.Engine.Group("prarthana_script")
 	app.Engine.GET("/health-check", ingestion.HealthCheck)
-	authRepo := zoho.InitZohoService(ctx, configuration, &http.Client{})
-	am := middleware.InitAuthMiddleware(configuration, authRepo)
+	authService, err := auth.InitAuthService(ctx, configuration, &http.Client{Timeout: 10 * time.Second})
+	if err != nil {
+		panic(fmt.Sprintf("Unable to initialize auth : %v", err))
+	}
+	am := middleware.InitAuthMiddleware(authService)
+	audit := middleware.InitAuditMiddleware(service.IngestionAuditService(), service.NotifierService())
+	cdn := middleware.InitCdnMiddleware(service.CdnInvalidationService())
 	//prarthana-script
 	{
 		prarthanaIngestionController := ingestion.InitIngestionController(ctx, service, configuration)
 		prarthanaIngestionV1 := basePath.Group("v1")
-		prarthanaIngestionV1.POST("/shloks", am.ZohoAuthMiddleware(), prarthanaIngestionController.ShlokIngestion)
-		prarthanaIngestionV1.POST("/stotras", am.ZohoAuthMiddleware(), prarthanaIngestionController.StotraIngestion)
-		prarthanaIngestionV1.POST("/prarthanas", am.ZohoAuthMiddleware(), prarthanaIngestionController.PrarthanaIngestion)
-		prarthanaIngestionV1.POST("/deities", am.ZohoAuthMiddleware(), prarthanaIngestionController.DeityIngestion)
+		prarthanaIngestionV1.POST("/shloks", am.RequireRole(entity.RolePublisher), audit.Audit(entity.ContentShloks), cdn.InvalidateChangedAssets(), prarthanaIngestionController.ShlokIngestion)
+		prarthanaIngestionV1.POST("/stotras", am.RequireRole(entity.RolePublisher), audit.Audit(entity.ContentStotras), cdn.InvalidateChangedAssets(), prarthanaIngestionController.StotraIngestion)
+		prarthanaIngestionV1.POST("/prarthanas", am.RequireRole(entity.RolePublisher), audit.Audit(entity.ContentPrarthanas), cdn.InvalidateChangedAssets(), prarthanaIngestionController.PrarthanaIngestion)
+		prarthanaIngestionV1.POST("/deities", am.RequireRole(entity.RolePublisher), audit.Audit(entity.ContentDeities), cdn.InvalidateChangedAssets(), prarthanaIngestionController.DeityIngestion)
+		prarthanaIngestionV1.POST("/search/reindex", am.RequireRole(entity.RolePublisher), audit.Audit(), prarthanaIngestionController.SearchReindex)
+		prarthanaIngestionV1.POST("/assets/:kind", am.RequireRole(entity.RoleEditor), audit.Audit(), cdn.InvalidateChangedAssets(), prarthanaIngestionController.UploadAssets)
+		for _, contentType := range []string{entity.ContentShloks, entity.ContentStotras, entity.ContentPrarthanas, entity.ContentDeities} {
+			prarthanaIngestionV1.GET("/"+contentType, am.RequireRole(entity.RoleViewer), prarthanaIngestionController.ListContent(contentType))
+			prarthanaIngestionV1.GET("/"+contentType+"/:key", am.RequireRole(entity.RoleViewer), prarthanaIngestionController.GetContent(contentType))
+		}
+		prarthanaIngestionV1.GET("/prarthanas/:key/resolved", am.RequireRole(entity.RoleViewer), prarthanaIngestionController.ResolvePrarthana)
+		prarthanaIngestionV1.GET("/snapshots", am.RequireRole(entity.RoleViewer), prarthanaIngestionController.ListSheetSnapshots)
+		prarthanaIngestionV1.GET("/snapshots/diff", am.RequireRole(entity.RoleViewer), prarthanaIngestionController.DiffSheetSnapshots)
+		prarthanaIngestionV1.GET("/snapshots/:id", am.RequireRole(entity.RoleViewer), prarthanaIngestionController.GetSheetSnapshot)
+		prarthanaIngestionV1.GET("/audit", am.RequireRole(entity.RoleViewer), prarthanaIngestionController.ListIngestionAudits)
+		prarthanaIngestionV1.GET("/change_events", am.RequireRole(entity.RoleViewer), prarthanaIngestionController.ListChangeEvents)
+		prarthanaIngestionV1.GET("/cdn/invalidations", am.RequireRole(entity.RoleViewer), prarthanaIngestionController.ListCdnInvalidations)
+		prarthanaIngestionV1.GET("/cdn/invalidations/:id", am.RequireRole(entity.RoleViewer), prarthanaIngestionController.GetCdnInvalidation)
+		prarthanaIngestionV1.POST("/cdn/invalidations", am.RequireRole(entity.RolePublisher), audit.Audit(), prarthanaIngestionController.CreateCdnInvalidation)
+		prarthanaIngestionV1.POST("/notifications/test", am.RequireRole(entity.RolePublisher), prarthanaIngestionController.SendTestNotification)
+	}
+	if configuration.StorageConfig.Backend == "local" {
+		app.Engine.Static("/assets", configuration.StorageConfig.LocalRoot)
 	}
 	app.Engine.LoadHTMLGlob("ingestion/*.html")
 	app.Engine.GET("/ingestion/prarthana.html", func(c *gin.Context) {
 		c.HTML(http.StatusOK, "index.html", configuration.UIConfig)
 	})
+	app.Engine.GET("/ingestion/audit.html", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "audit.html", configuration.UIConfig)
+	})
 }
@@ -5,35 +5,122 @@ import (
 	"fmt"
 	"github.com/Out-Of-India-Theory/oit-go-commons/app"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/broker"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/cdn"
+	esPrarthana "github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/es/prarthana"
+	cdnInvalidationRepo "github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/cdn_invalidation"
+	changeOutboxRepo "github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/change_outbox"
+	ingestionAuditRepo "github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/ingestion_audit"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/job_lease"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/prarthana_data"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/schema_migration"
+	sheetSnapshotRepo "github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/sheet_snapshot"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/storage"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_upload"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_url"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_verifier"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/audio_stitching"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/cdn_invalidation"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/change_events"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/content_read"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/deity_ingestion"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/deity_prarthana_link"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/facade"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/ingestion_audit"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/notifier"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/prarthana_ingestion"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/scheduler"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/search_indexing"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/sheet_snapshot"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/shlok_ingestion"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/stotra_ingestion"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/transcoding"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/waveform"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/zoho"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/zoho_token"
 	"github.com/gin-gonic/gin"
 	"github.com/newrelic/go-agent/v3/newrelic"
+	"log"
 	"net/http"
+	"slices"
 )
 
 func InitServer(ctx context.Context, app *app.App, configuration *configuration.Configuration) {
+	if configuration.MigrationConfig.RunOnStartup {
+		if err := RunMigrations(ctx, configuration); err != nil {
+			panic(fmt.Sprintf("Unable to apply schema migrations : %v", err))
+		}
+	}
 	//repo initializations
+	changeOutboxMongoRepository := changeOutboxRepo.InitChangeOutboxMongoRepository(ctx, *configuration)
+	jobLeaseMongoRepository := job_lease.InitJobLeaseMongoRepository(ctx, *configuration)
 	prarthanaDataMongoRepository := prarthana_data.InitPrarthanaDataMongoRepository(ctx, *configuration)
+	ingestionAuditMongoRepository := ingestionAuditRepo.InitIngestionAuditMongoRepository(ctx, *configuration)
+	sheetSnapshotMongoRepository := sheetSnapshotRepo.InitSheetSnapshotMongoRepository(ctx, *configuration)
+	cdnInvalidationMongoRepository := cdnInvalidationRepo.InitCdnInvalidationMongoRepository(ctx, *configuration)
+	prarthanaElasticRepository := esPrarthana.InitPrarthanaElasticRepository(ctx, *configuration, &http.Client{Timeout: configuration.ElasticConfig.Timeout})
+	assetStorage, err := storage.InitStorage(ctx, *configuration)
+	if err != nil {
+		panic(fmt.Sprintf("Unable to initialize asset storage : %v", err))
+	}
+	var eventPublisher broker.Publisher
+	if configuration.ChangeEventConfig.Enabled {
+		if eventPublisher, err = broker.InitPublisher(ctx, *configuration); err != nil {
+			panic(fmt.Sprintf("Unable to initialize change event publisher : %v", err))
+		}
+	}
+	var cdnClient cdn.Client
+	if configuration.CdnConfig.Enabled {
+		if cdnClient, err = cdn.InitClient(ctx, *configuration); err != nil {
+			panic(fmt.Sprintf("Unable to initialize cdn client : %v", err))
+		}
+	}
 
-	zohoService := zoho.InitZohoService(ctx, configuration, &http.Client{})
+	zohoTokenService := zoho_token.InitZohoTokenService(ctx, configuration, &http.Client{})
+	sheetSnapshotService := sheet_snapshot.InitSheetSnapshotService(ctx, configuration, sheetSnapshotMongoRepository)
+	zohoService := zoho.InitZohoService(ctx, configuration, &http.Client{}, zohoTokenService, sheetSnapshotService)
 	//service initializations
+	assetUrlService := asset_url.InitAssetUrlService(ctx, configuration)
+	assetVerifierService := asset_verifier.InitAssetVerifierService(ctx, configuration)
+	assetStorage = storage.WithPutListener(assetStorage, func(key string) {
+		assetVerifierService.Evict(assetUrlService.UrlOfPath(key))
+	})
+	assetUploadService := asset_upload.InitAssetUploadService(ctx, configuration, assetStorage, assetUrlService)
+	waveformService := waveform.InitWaveformService(ctx, configuration, assetStorage, assetUrlService)
+	transcodingService := transcoding.InitTranscodingService(ctx, configuration, assetStorage, assetUrlService)
+	audioStitchingService := audio_stitching.InitAudioStitchingService(ctx, configuration, assetStorage, assetUrlService, waveformService, transcodingService)
+	ingestionAuditService := ingestion_audit.InitIngestionAuditService(ctx, ingestionAuditMongoRepository)
+	contentReadService := content_read.InitContentReadService(ctx, prarthanaDataMongoRepository)
+	notifierService, err := notifier.InitNotifierService(ctx, configuration, &http.Client{Timeout: configuration.NotifierConfig.Timeout})
+	if err != nil {
+		panic(fmt.Sprintf("Unable to initialize notifier : %v", err))
+	}
+	changeEventService := change_events.InitChangeEventService(ctx, configuration, changeOutboxMongoRepository, jobLeaseMongoRepository, eventPublisher)
+	cdnInvalidationService := cdn_invalidation.InitCdnInvalidationService(ctx, configuration, cdnInvalidationMongoRepository, cdnClient)
+	searchIndexingService := search_indexing.InitSearchIndexingService(ctx, configuration, prarthanaDataMongoRepository, prarthanaElasticRepository)
+	deityPrarthanaLinkService := deity_prarthana_link.InitDeityPrarthanaLinkService(ctx, prarthanaDataMongoRepository, zohoService)
 	shlokIngestionService := shlok_ingestion.InitShlokIngestionService(ctx, prarthanaDataMongoRepository, zohoService)
-	stotraIngestionService := stotra_ingestion.InitStotraIngestionService(ctx, prarthanaDataMongoRepository, zohoService)
-	prarthanaIngestionService := prarthana_ingestion.InitPrathanaIngestionService(ctx, prarthanaDataMongoRepository, zohoService)
-	deityIngestionService := deity_ingestion.InitDeityIngestionService(ctx, prarthanaDataMongoRepository, zohoService)
+	stotraIngestionService := stotra_ingestion.InitStotraIngestionService(ctx, configuration, prarthanaDataMongoRepository, zohoService, searchIndexingService, assetUrlService, assetVerifierService, waveformService, transcodingService)
+	prarthanaIngestionService := prarthana_ingestion.InitPrathanaIngestionService(ctx, configuration, prarthanaDataMongoRepository, zohoService, searchIndexingService, deityPrarthanaLinkService, assetUrlService, assetVerifierService, audioStitchingService, transcodingService, &http.Client{Timeout: configuration.TranscodingConfig.DownloadTimeout})
+	deityIngestionService := deity_ingestion.InitDeityIngestionService(ctx, configuration, prarthanaDataMongoRepository, zohoService, searchIndexingService, deityPrarthanaLinkService, assetUrlService, assetVerifierService, assetStorage)
 
-	facadeService := facade.InitFacadeService(ctx, configuration, shlokIngestionService, stotraIngestionService, prarthanaIngestionService, deityIngestionService, zohoService)
+	facadeService := facade.InitFacadeService(ctx, configuration, shlokIngestionService, stotraIngestionService, prarthanaIngestionService, deityIngestionService, zohoService, searchIndexingService, assetUploadService, ingestionAuditService, contentReadService, sheetSnapshotService, notifierService, changeEventService, cdnInvalidationService)
+	if configuration.ChangeEventConfig.Enabled {
+		if err = changeEventService.Start(ctx); err != nil {
+			panic(fmt.Sprintf("Unable to start the change event relay : %v", err))
+		}
+	}
+	if configuration.SchedulerConfig.Enabled {
+		schedulerService := scheduler.InitSchedulerService(ctx, configuration, jobLeaseMongoRepository, shlokIngestionService, stotraIngestionService, prarthanaIngestionService, deityIngestionService, ingestionAuditService, notifierService, cdnInvalidationService)
+		if err = schedulerService.Start(ctx); err != nil {
+			panic(fmt.Sprintf("Unable to start the scheduler : %v", err))
+		}
+	}
 	registerMiddleware(app, configuration)
 	registerRoutes(ctx, app, facadeService, configuration)
 
 	app.StartHttpServer()
-	err := app.StartMetricsServer()
+	err = app.StartMetricsServer()
 	if err != nil {
 		panic("Error while initializing http client")
 	}
@@ -41,6 +128,15 @@ func InitServer(ctx context.Context, app *app.App, configuration *configuration.
 	<-make(chan int)
 }
 
+func RunMigrations(ctx context.Context, configuration *configuration.Configuration) error {
+	schemaMigrationMongoRepository := schema_migration.InitSchemaMigrationMongoRepository(ctx, *configuration)
+	applied, err := schemaMigrationMongoRepository.Migrate(ctx)
+	for _, m := range applied {
+		log.Printf("Applied schema migration %d: %s\n", m.Version, m.Description)
+	}
+	return err
+}
+
 func registerMiddleware(app *app.App, configuration *configuration.Configuration) {
 	newrelicApp, err := newrelic.NewApplication(
 		newrelic.ConfigAppName(fmt.Sprintf("%s-%s", app.Config.AppName, app.Config.Env)),
@@ -53,7 +149,7 @@ func registerMiddleware(app *app.App, configuration *configuration.Configuration
 	}
 	//app.Engine.Use(nrgin.Middleware(newrelicApp))
 	app.Engine.Use(newrelicTransactionMiddleware(newrelicApp))
-	app.Engine.Use(CORSMiddleware())
+	app.Engine.Use(CORSMiddleware(configuration.CorsConfig.AllowedOrigins))
 }
 
 func newrelicTransactionMiddleware(newRelicApp *newrelic.Application) gin.HandlerFunc {
@@ -65,13 +161,18 @@ func newrelicTransactionMiddleware(newRelicApp *newrelic.Application) gin.Handle
 	}
 }
 
-func CORSMiddleware() gin.HandlerFunc {
+// CORSMiddleware only grants cross-origin access to the configured origins, echoing the
+// caller's origin back instead of "*" so credentials stay scoped to trusted pages.
+func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, source")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT")
-		c.Writer.Header().Set("source", "*")
+		origin := c.Request.Header.Get("Origin")
+		if origin != "" && slices.Contains(allowedOrigins, origin) {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, accept, origin, Cache-Control, X-Requested-With, source")
+			c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT")
+		}
+		c.Writer.Header().Add("Vary", "Origin")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -0,0 +1,178 @@
+package asset_upload
+
+import (
+	"context"
+	"fmt"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/storage"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_url"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+	"go.uber.org/zap"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type uploadRule struct {
+	extensions   []string
+	contentTypes []string
+	audio        bool
+}
+
+var audioRule = uploadRule{
+	extensions: []string{".wav", ".mp3"},
+	audio:      true,
+}
+
+var audioContentTypes = map[string]string{
+	".wav": "audio/wav",
+	".mp3": "audio/mpeg",
+}
+
+var imageRule = uploadRule{
+	extensions:   []string{".png"},
+	contentTypes: []string{"image/png"},
+}
+
+// uploadRules lists the asset kinds that can be uploaded and what they accept.
+var uploadRules = map[string]uploadRule{
+	asset_url.StotraAudio:        audioRule,
+	asset_url.StitchedAudio:      audioRule,
+	asset_url.AlbumArt:           imageRule,
+	asset_url.DeityListImage:     imageRule,
+	asset_url.DeityBgImage:       imageRule,
+	asset_url.HeroFullImage:      imageRule,
+	asset_url.HeroThumbnailImage: imageRule,
+	asset_url.HeroShareImage:     imageRule,
+	asset_url.DodImage:           imageRule,
+}
+
+// mirrors the name check ingestion applies to sheet names before deriving file names
+var specialCharacters = regexp.MustCompile(`[^a-zA-Z0-9\s\-\(\)\[\]_]+`)
+
+type AssetUploadService struct {
+	logger          *zap.Logger
+	config          configuration.UploadConfig
+	storage         storage.Storage
+	assetUrlService asset_url.Service
+}
+
+func InitAssetUploadService(ctx context.Context,
+	configuration *configuration.Configuration,
+	storage storage.Storage,
+	assetUrlService asset_url.Service,
+) *AssetUploadService {
+	return &AssetUploadService{
+		logger:          logging.WithContext(ctx),
+		config:          configuration.UploadConfig,
+		storage:         storage,
+		assetUrlService: assetUrlService,
+	}
+}
+
+// Upload validates every file first and only uploads when all of them pass, so a batch is
+// never half uploaded. Files are renamed with the same rules ingestion uses to derive asset
+// names from the sheet, so the returned URLs are the ones ingestion will look for.
+func (s *AssetUploadService) Upload(ctx context.Context, kind string, files []*multipart.FileHeader) ([]entity.UploadedAsset, error) {
+	rule, ok := uploadRules[kind]
+	if !ok {
+		return nil, &entity.InvalidUploadError{Problems: []string{fmt.Sprintf("unsupported asset kind: %s", kind)}}
+	}
+	if len(files) == 0 {
+		return nil, &entity.InvalidUploadError{Problems: []string{"no files provided"}}
+	}
+
+	var problems []string
+	assets := make([]entity.UploadedAsset, 0, len(files))
+	contentTypes := make([]string, 0, len(files))
+	for _, file := range files {
+		asset, contentType, err := s.prepare(kind, rule, file)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		assets = append(assets, asset)
+		contentTypes = append(contentTypes, contentType)
+	}
+	if len(problems) > 0 {
+		return nil, &entity.InvalidUploadError{Problems: problems}
+	}
+
+	for i, file := range files {
+		if err := s.put(ctx, assets[i].Key, contentTypes[i], file); err != nil {
+			return assets[:i], err
+		}
+		log.Printf("Uploaded %s to %s\n", file.Filename, assets[i].Key)
+	}
+	return assets, nil
+}
+
+func (s *AssetUploadService) prepare(kind string, rule uploadRule, file *multipart.FileHeader) (entity.UploadedAsset, string, error) {
+	extension := strings.ToLower(filepath.Ext(file.Filename))
+	baseName := strings.TrimSuffix(file.Filename, filepath.Ext(file.Filename))
+	if !slices.Contains(rule.extensions, extension) {
+		return entity.UploadedAsset{}, "", fmt.Errorf("file %q has an unsupported extension, expected %s", file.Filename, strings.Join(rule.extensions, " or "))
+	}
+	if specialCharacters.MatchString(baseName) {
+		return entity.UploadedAsset{}, "", fmt.Errorf("file %q has special characters in its name", file.Filename)
+	}
+	maxSize := s.config.MaxImageSize
+	if rule.audio {
+		maxSize = s.config.MaxAudioSize
+	}
+	if maxSize > 0 && file.Size > maxSize {
+		return entity.UploadedAsset{}, "", fmt.Errorf("file %q is %d bytes, larger than the %d byte limit", file.Filename, file.Size, maxSize)
+	}
+	contentType, err := sniffContentType(file)
+	if err != nil {
+		return entity.UploadedAsset{}, "", fmt.Errorf("file %q could not be read: %v", file.Filename, err)
+	}
+	if len(rule.contentTypes) > 0 && !slices.Contains(rule.contentTypes, contentType) {
+		return entity.UploadedAsset{}, "", fmt.Errorf("file %q is %s, expected %s", file.Filename, contentType, strings.Join(rule.contentTypes, " or "))
+	}
+	if rule.audio {
+		contentType = audioContentTypes[extension]
+	}
+
+	name := strings.ToLower(util.SanitizeString(baseName))
+	// kinds whose template fixes the extension take the bare name
+	if s.assetUrlService.Extension(kind) == "" {
+		name += extension
+	}
+	return entity.UploadedAsset{
+		FileName: file.Filename,
+		Name:     name,
+		Key:      s.assetUrlService.Path(kind, name),
+		Url:      s.assetUrlService.Url(kind, name),
+	}, contentType, nil
+}
+
+func (s *AssetUploadService) put(ctx context.Context, key string, contentType string, file *multipart.FileHeader) error {
+	src, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", file.Filename, err)
+	}
+	defer src.Close()
+	return s.storage.Put(ctx, key, contentType, src, file.Size)
+}
+
+func sniffContentType(file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	header := make([]byte, 512)
+	n, err := io.ReadFull(src, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	return http.DetectContentType(header[:n]), nil
+}
@@ -0,0 +1,11 @@
+package asset_upload
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"mime/multipart"
+)
+
+type Service interface {
+	Upload(ctx context.Context, kind string, files []*multipart.FileHeader) ([]entity.UploadedAsset, error)
+}
@@ -0,0 +1,135 @@
+package asset_url
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"go.uber.org/zap"
+	"path"
+	"strings"
+)
+
+// Asset kinds. Each kind has a path template in which {name} is replaced by the asset file
+// name; audio names carry their own extension, image and waveform templates fix theirs.
+const (
+	StotraAudio        = "stotra_audio"
+	StitchedAudio      = "stitched_audio"
+	AlbumArt           = "album_art"
+	DeityListImage     = "deity_list_image"
+	DeityBgImage       = "deity_bg_image"
+	HeroFullImage      = "hero_full_image"
+	HeroThumbnailImage = "hero_thumbnail_image"
+	HeroShareImage     = "hero_share_image"
+	DodImage           = "dod_image"
+	StotraWaveform     = "stotra_waveform"
+	StitchedWaveform   = "stitched_waveform"
+	AudioRendition     = "audio_rendition"
+	AudioHls           = "audio_hls"
+)
+
+const namePlaceholder = "{name}"
+
+var defaultTemplates = map[string]string{
+	StotraAudio:        "audio/{name}",
+	StitchedAudio:      "audio/stitched_audio/{name}",
+	AlbumArt:           "prarthanas/album_art/{name}.png",
+	DeityListImage:     "prarthanas/deities/list-image/{name}.png",
+	DeityBgImage:       "prarthanas/deities/bg-image/{name}.png",
+	HeroFullImage:      "prarthanas/deities/hero_image_album/full_image/{name}.png",
+	HeroThumbnailImage: "prarthanas/deities/hero_image_album/thumbnail_image/{name}.png",
+	HeroShareImage:     "prarthanas/deities/hero_image_album/share_image/{name}.png",
+	DodImage:           "prarthanas/deities/hero_image_album/dod_image/{name}.png",
+	StotraWaveform:     "audio/waveforms/{name}.json",
+	StitchedWaveform:   "audio/stitched_audio/waveforms/{name}.json",
+	AudioRendition:     "audio/renditions/{name}",
+	AudioHls:           "audio/hls/{name}",
+}
+
+type AssetUrlService struct {
+	logger    *zap.Logger
+	baseUrl   string
+	templates map[string]string
+}
+
+// InitAssetUrlService resolves the templates for the running environment: built-in defaults,
+// overridden by AssetConfig.Templates, overridden by AssetConfig.Environments[<Env>].
+func InitAssetUrlService(ctx context.Context, configuration *configuration.Configuration) *AssetUrlService {
+	assetConfig := configuration.AssetConfig
+	baseUrl := assetConfig.BaseUrl
+	templates := make(map[string]string)
+	for kind, template := range defaultTemplates {
+		templates[kind] = template
+	}
+	for kind, template := range assetConfig.Templates {
+		templates[strings.ToLower(kind)] = template
+	}
+	if envConfig, ok := assetConfig.Environments[strings.ToLower(configuration.ServerConfig.Env)]; ok {
+		if envConfig.BaseUrl != "" {
+			baseUrl = envConfig.BaseUrl
+		}
+		for kind, template := range envConfig.Templates {
+			templates[strings.ToLower(kind)] = template
+		}
+	}
+	return &AssetUrlService{
+		logger:    logging.WithContext(ctx),
+		baseUrl:   strings.TrimSuffix(baseUrl, "/"),
+		templates: templates,
+	}
+}
+
+// Path returns the storage key of an asset, e.g. "prarthanas/album_art/ganesh.png".
+func (s *AssetUrlService) Path(kind string, name string) string {
+	template, ok := s.templates[kind]
+	if !ok {
+		template = kind + "/" + namePlaceholder
+	}
+	return strings.ReplaceAll(template, namePlaceholder, name)
+}
+
+// Prefix returns the part of the kind's storage keys before the asset name, for listing.
+func (s *AssetUrlService) Prefix(kind string) string {
+	prefix, _, _ := strings.Cut(s.Path(kind, namePlaceholder), namePlaceholder)
+	return prefix
+}
+
+// NameFromPath returns the asset name of a storage key of the kind, or false if the key
+// does not follow the kind's template.
+func (s *AssetUrlService) NameFromPath(kind string, key string) (string, bool) {
+	prefix, suffix, _ := strings.Cut(s.Path(kind, namePlaceholder), namePlaceholder)
+	if !strings.HasPrefix(key, prefix) || !strings.HasSuffix(key, suffix) || len(key) <= len(prefix)+len(suffix) {
+		return "", false
+	}
+	name := key[len(prefix) : len(key)-len(suffix)]
+	if strings.Contains(name, "/") {
+		return "", false
+	}
+	return name, true
+}
+
+func (s *AssetUrlService) Url(kind string, name string) string {
+	return s.UrlOfPath(s.Path(kind, name))
+}
+
+// UrlOfPath returns the URL an asset is served from given its storage key.
+func (s *AssetUrlService) UrlOfPath(key string) string {
+	return s.baseUrl + "/" + key
+}
+
+// PathFromUrl returns the storage key of a URL produced by Url, or false if it points elsewhere.
+func (s *AssetUrlService) PathFromUrl(url string) (string, bool) {
+	if !strings.HasPrefix(url, s.baseUrl+"/") {
+		return "", false
+	}
+	return strings.TrimPrefix(url, s.baseUrl+"/"), true
+}
+
+// Extension returns the extension fixed by the kind's template, e.g. ".png", or "" when
+// the asset name carries its own extension.
+func (s *AssetUrlService) Extension(kind string) string {
+	template, ok := s.templates[kind]
+	if !ok || strings.HasSuffix(template, namePlaceholder) {
+		return ""
+	}
+	return path.Ext(template)
+}
@@ -0,0 +1,11 @@
+package asset_url
+
+type Service interface {
+	Path(kind string, name string) string
+	Url(kind string, name string) string
+	UrlOfPath(key string) string
+	Prefix(kind string) string
+	NameFromPath(kind string, key string) (string, bool)
+	PathFromUrl(url string) (string, bool)
+	Extension(kind string) string
+}
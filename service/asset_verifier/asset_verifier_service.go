@@ -0,0 +1,270 @@
+package asset_verifier
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"go.uber.org/zap"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type urlStatus struct {
+	status       entity.AssetStatus
+	statusCode   int
+	location     string
+	problems     []string
+	etag         string
+	lastModified string
+}
+
+type cachedStatus struct {
+	status     urlStatus
+	verifiedAt time.Time
+}
+
+type AssetVerifierService struct {
+	logger      *zap.Logger
+	httpClient  *http.Client
+	concurrency int
+	cacheTTL    time.Duration
+	imageSpecs  map[string]configuration.ImageSpecConfig
+	mu          sync.Mutex
+	cache       map[string]cachedStatus
+}
+
+func InitAssetVerifierService(ctx context.Context, configuration *configuration.Configuration) *AssetVerifierService {
+	verifierConfig := configuration.AssetVerifierConfig
+	concurrency := verifierConfig.Concurrency
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+	return &AssetVerifierService{
+		logger: logging.WithContext(ctx),
+		httpClient: &http.Client{
+			Timeout: verifierConfig.Timeout,
+			Transport: &http.Transport{
+				MaxIdleConnsPerHost: concurrency,
+			},
+			// a redirect means the object is not where the URL convention says it is
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		concurrency: concurrency,
+		cacheTTL:    verifierConfig.CacheTTL,
+		imageSpecs:  verifierConfig.ImageSpecs,
+		cache:       make(map[string]cachedStatus),
+	}
+}
+
+// Verify checks every candidate URL of the batch concurrently, then resolves each check to
+// its first found candidate. Images of a kind with an image spec are downloaded and
+// inspected, and a violating image counts as invalid rather than found. Only found URLs are
+// cached, with their ETag and Last-Modified, so a freshly uploaded asset is picked up on the
+// next run. The error lists every check with no found candidate.
+func (s *AssetVerifierService) Verify(ctx context.Context, contentType string, checks []entity.AssetCheck) ([]entity.AssetCheckResult, *entity.MissingAssetsError) {
+	return s.verify(ctx, contentType, checks, true)
+}
+
+// VerifyFresh is Verify without reading the cache, for callers that hash the ETag and
+// Last-Modified of the results: a cached entry would hide an asset replaced since.
+func (s *AssetVerifierService) VerifyFresh(ctx context.Context, contentType string, checks []entity.AssetCheck) ([]entity.AssetCheckResult, *entity.MissingAssetsError) {
+	return s.verify(ctx, contentType, checks, false)
+}
+
+// Evict drops the URL from the cache once the object behind it is overwritten.
+func (s *AssetVerifierService) Evict(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cache, url)
+}
+
+func (s *AssetVerifierService) verify(ctx context.Context, contentType string, checks []entity.AssetCheck, useCache bool) ([]entity.AssetCheckResult, *entity.MissingAssetsError) {
+	statuses := s.headAll(ctx, checks, useCache)
+
+	results := make([]entity.AssetCheckResult, 0, len(checks))
+	missingByRow := make(map[int][]entity.MissingAsset)
+	total := 0
+	for _, check := range checks {
+		result := entity.AssetCheckResult{AssetCheck: check, Status: entity.AssetMissing}
+		for _, candidate := range check.Candidates {
+			status := statuses[candidate]
+			if status.status == entity.AssetFound {
+				result.Status, result.Url, result.StatusCode, result.Location = status.status, candidate, status.statusCode, ""
+				result.Problems = nil
+				result.ETag, result.LastModified = status.etag, status.lastModified
+				break
+			}
+			// report the first candidate's failure, it is the preferred file name
+			if result.Url == "" {
+				result.Status, result.Url, result.StatusCode, result.Location = status.status, candidate, status.statusCode, status.location
+				result.Problems = status.problems
+			}
+		}
+		results = append(results, result)
+		if result.Status != entity.AssetFound {
+			total++
+			missingByRow[check.Row] = append(missingByRow[check.Row], entity.MissingAsset{
+				Kind:             check.Kind,
+				ExpectedFileName: expectedFileName(check.Candidates),
+				Url:              result.Url,
+				Status:           result.Status,
+				StatusCode:       result.StatusCode,
+				Location:         result.Location,
+				Problems:         result.Problems,
+			})
+		}
+	}
+	if total == 0 {
+		return results, nil
+	}
+
+	report := entity.MissingAssetsReport{ContentType: contentType, Total: total}
+	for row, assets := range missingByRow {
+		report.Rows = append(report.Rows, entity.MissingAssetsRow{Row: row, Assets: assets})
+	}
+	sort.Slice(report.Rows, func(i, j int) bool { return report.Rows[i].Row < report.Rows[j].Row })
+	return results, &entity.MissingAssetsError{Report: report}
+}
+
+func (s *AssetVerifierService) headAll(ctx context.Context, checks []entity.AssetCheck, useCache bool) map[string]urlStatus {
+	statuses := make(map[string]urlStatus)
+	var urls []string
+	kinds := make(map[string]string)
+	for _, check := range checks {
+		for _, candidate := range check.Candidates {
+			if _, ok := statuses[candidate]; ok {
+				continue
+			}
+			statuses[candidate] = urlStatus{}
+			if status, ok := s.cached(candidate); ok && useCache {
+				statuses[candidate] = status
+				continue
+			}
+			urls = append(urls, candidate)
+			kinds[candidate] = check.Kind
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, s.concurrency)
+	for _, url := range urls {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(url string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			var status urlStatus
+			if spec, ok := s.imageSpecs[kinds[url]]; ok {
+				status = s.inspect(ctx, url, spec)
+			} else {
+				status = s.head(ctx, url)
+			}
+			if status.status == entity.AssetFound {
+				s.markCached(url, status)
+			}
+			mu.Lock()
+			statuses[url] = status
+			mu.Unlock()
+		}(url)
+	}
+	wg.Wait()
+	return statuses
+}
+
+func (s *AssetVerifierService) head(ctx context.Context, url string) urlStatus {
+	resp, err := s.request(ctx, http.MethodHead, url)
+	if err != nil {
+		log.Printf("Error checking URL: %s, %v", url, err)
+		return urlStatus{status: entity.AssetUnreachable}
+	}
+	defer resp.Body.Close()
+	return statusOf(resp)
+}
+
+// inspect downloads the image and checks it against the spec of its kind.
+func (s *AssetVerifierService) inspect(ctx context.Context, url string, spec configuration.ImageSpecConfig) urlStatus {
+	resp, err := s.request(ctx, http.MethodGet, url)
+	if err != nil {
+		log.Printf("Error checking URL: %s, %v", url, err)
+		return urlStatus{status: entity.AssetUnreachable}
+	}
+	defer resp.Body.Close()
+	status := statusOf(resp)
+	if status.status != entity.AssetFound {
+		return status
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Printf("Error downloading URL: %s, %v", url, err)
+		return urlStatus{status: entity.AssetUnreachable, statusCode: resp.StatusCode}
+	}
+	if problems := inspectImage(body, spec); len(problems) > 0 {
+		return urlStatus{status: entity.AssetInvalid, statusCode: resp.StatusCode, problems: problems}
+	}
+	return status
+}
+
+func (s *AssetVerifierService) request(ctx context.Context, method, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.httpClient.Do(req)
+}
+
+func statusOf(resp *http.Response) urlStatus {
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return urlStatus{
+			status:       entity.AssetFound,
+			statusCode:   resp.StatusCode,
+			etag:         resp.Header.Get("ETag"),
+			lastModified: resp.Header.Get("Last-Modified"),
+		}
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return urlStatus{status: entity.AssetRedirected, statusCode: resp.StatusCode, location: resp.Header.Get("Location")}
+	case resp.StatusCode == http.StatusForbidden:
+		// S3 behind CloudFront answers 403 for absent keys when listing is not allowed
+		return urlStatus{status: entity.AssetForbidden, statusCode: resp.StatusCode}
+	case resp.StatusCode == http.StatusNotFound:
+		return urlStatus{status: entity.AssetMissing, statusCode: resp.StatusCode}
+	default:
+		return urlStatus{status: entity.AssetUnreachable, statusCode: resp.StatusCode}
+	}
+}
+
+func (s *AssetVerifierService) cached(url string) (urlStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.cache[url]
+	if !ok || time.Since(entry.verifiedAt) >= s.cacheTTL {
+		return urlStatus{}, false
+	}
+	return entry.status, true
+}
+
+func (s *AssetVerifierService) markCached(url string, status urlStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache[url] = cachedStatus{status: status, verifiedAt: time.Now()}
+}
+
+func expectedFileName(candidates []string) string {
+	names := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		names = append(names, path.Base(candidate))
+	}
+	return strings.Join(names, " or ")
+}
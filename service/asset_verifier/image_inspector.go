@@ -0,0 +1,83 @@
+package asset_verifier
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+)
+
+const (
+	transparencyRequired  = "required"
+	transparencyForbidden = "forbidden"
+
+	defaultAspectTolerance = 0.01
+)
+
+// inspectImage checks the downloaded image against the spec of its kind and returns one
+// message per violation. Pixels are only decoded when the spec constrains transparency.
+func inspectImage(body []byte, spec configuration.ImageSpecConfig) []string {
+	var problems []string
+	if spec.MaxBytes > 0 && int64(len(body)) > spec.MaxBytes {
+		problems = append(problems, fmt.Sprintf("size %d bytes exceeds %d", len(body), spec.MaxBytes))
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return append(problems, fmt.Sprintf("not a readable image: %v", err))
+	}
+	if spec.Format != "" && format != spec.Format {
+		problems = append(problems, fmt.Sprintf("format is %s, expected %s", format, spec.Format))
+	}
+	if spec.MinWidth > 0 && config.Width < spec.MinWidth || spec.MinHeight > 0 && config.Height < spec.MinHeight {
+		problems = append(problems, fmt.Sprintf("%dx%d is smaller than %dx%d", config.Width, config.Height, spec.MinWidth, spec.MinHeight))
+	}
+	if spec.MaxWidth > 0 && config.Width > spec.MaxWidth || spec.MaxHeight > 0 && config.Height > spec.MaxHeight {
+		problems = append(problems, fmt.Sprintf("%dx%d is larger than %dx%d", config.Width, config.Height, spec.MaxWidth, spec.MaxHeight))
+	}
+	if spec.AspectRatio > 0 && config.Height > 0 {
+		tolerance := spec.AspectTolerance
+		if tolerance <= 0 {
+			tolerance = defaultAspectTolerance
+		}
+		ratio := float64(config.Width) / float64(config.Height)
+		if math.Abs(ratio-spec.AspectRatio) > spec.AspectRatio*tolerance {
+			problems = append(problems, fmt.Sprintf("aspect ratio %.3f, expected %.3f", ratio, spec.AspectRatio))
+		}
+	}
+
+	if spec.Transparency == "" {
+		return problems
+	}
+	img, _, err := image.Decode(bytes.NewReader(body))
+	if err != nil {
+		return append(problems, fmt.Sprintf("not a decodable image: %v", err))
+	}
+	transparent := hasTransparency(img)
+	switch {
+	case spec.Transparency == transparencyRequired && !transparent:
+		problems = append(problems, "expected a transparent background")
+	case spec.Transparency == transparencyForbidden && transparent:
+		problems = append(problems, "contains transparent pixels")
+	}
+	return problems
+}
+
+func hasTransparency(img image.Image) bool {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok {
+		return !opaque.Opaque()
+	}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a < 0xffff {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,12 @@
+package asset_verifier
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type Service interface {
+	Verify(ctx context.Context, contentType string, checks []entity.AssetCheck) ([]entity.AssetCheckResult, *entity.MissingAssetsError)
+	VerifyFresh(ctx context.Context, contentType string, checks []entity.AssetCheck) ([]entity.AssetCheckResult, *entity.MissingAssetsError)
+	Evict(url string)
+}
@@ -0,0 +1,178 @@
+package audio_stitching
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/storage"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_url"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/transcoding"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/waveform"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+	"go.uber.org/zap"
+)
+
+type AudioStitchingService struct {
+	logger             *zap.Logger
+	config             configuration.StitchingConfig
+	storage            storage.Storage
+	assetUrlService    asset_url.Service
+	waveformService    waveform.Service
+	transcodingService transcoding.Service
+}
+
+func InitAudioStitchingService(ctx context.Context,
+	configuration *configuration.Configuration,
+	storage storage.Storage,
+	assetUrlService asset_url.Service,
+	waveformService waveform.Service,
+	transcodingService transcoding.Service,
+) *AudioStitchingService {
+	config := configuration.StitchingConfig
+	if config.SampleRate <= 0 {
+		config.SampleRate = 44100
+	}
+	if config.Channels <= 0 {
+		config.Channels = 2
+	}
+	return &AudioStitchingService{
+		logger:             logging.WithContext(ctx),
+		config:             config,
+		storage:            storage,
+		assetUrlService:    assetUrlService,
+		waveformService:    waveformService,
+		transcodingService: transcodingService,
+	}
+}
+
+// Stitch concatenates the stotra audio of the variant's chapters in order into
+// stitched_audio/<name>.wav on the storage backend. Every stotra is converted to the
+// configured sample rate and channel count, and the returned offsets are measured on the
+// written samples, so they match the file exactly.
+func (s *AudioStitchingService) Stitch(ctx context.Context, name string, variant entity.Variant, stotras map[string]entity.Stotra) (entity.StitchedAudio, error) {
+	file, err := os.CreateTemp("", "stitched-*.wav")
+	if err != nil {
+		return entity.StitchedAudio{}, fmt.Errorf("error creating stitched audio file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	st := &stitcher{
+		writer:          util.NewWavWriter(file, s.config.SampleRate, s.config.Channels),
+		sampleRate:      s.config.SampleRate,
+		channels:        s.config.Channels,
+		crossfadeFrames: s.msToFrames(s.config.CrossfadeMs),
+	}
+	if s.waveformService.Enabled() {
+		st.waveform = util.NewWaveformBuilder(s.config.SampleRate, s.config.Channels)
+	}
+	var chapters []entity.ChapterOffset
+	for i, chapter := range variant.Chapters {
+		if i > 0 {
+			if err := st.silence(s.msToFrames(s.config.ChapterGapMs)); err != nil {
+				return entity.StitchedAudio{}, err
+			}
+		}
+		chapterStart := -1
+		for j, stotraId := range chapter.StotraIds {
+			stotra, ok := stotras[stotraId]
+			if !ok {
+				return entity.StitchedAudio{}, fmt.Errorf("chapter %d of %s references unknown stotra %s", i+1, name, stotraId)
+			}
+			clip, err := s.load(ctx, stotra)
+			if err != nil {
+				return entity.StitchedAudio{}, fmt.Errorf("error loading audio of stotra %s: %w", stotraId, err)
+			}
+			if j > 0 {
+				if err := st.silence(s.msToFrames(s.config.GapMs)); err != nil {
+					return entity.StitchedAudio{}, err
+				}
+			}
+			start, err := st.add(clip.Convert(s.config.SampleRate, s.config.Channels))
+			if err != nil {
+				return entity.StitchedAudio{}, err
+			}
+			if chapterStart < 0 {
+				chapterStart = start
+			}
+		}
+		if chapterStart < 0 {
+			chapterStart = st.end()
+		}
+		chapters = append(chapters, entity.ChapterOffset{
+			StartMs:    s.framesToMs(chapterStart),
+			DurationMs: s.framesToMs(st.end() - chapterStart),
+		})
+	}
+	if err := st.close(); err != nil {
+		return entity.StitchedAudio{}, err
+	}
+
+	key := s.assetUrlService.Path(asset_url.StitchedAudio, name+".wav")
+	if err := s.upload(ctx, file, key); err != nil {
+		return entity.StitchedAudio{}, err
+	}
+	stitched := entity.StitchedAudio{
+		Url:        s.assetUrlService.Url(asset_url.StitchedAudio, name+".wav"),
+		DurationMs: s.framesToMs(st.written),
+		Chapters:   chapters,
+	}
+	if st.waveform != nil {
+		if stitched.WaveformUrl, err = s.waveformService.Save(ctx, asset_url.StitchedWaveform, name, st.waveform); err != nil {
+			return entity.StitchedAudio{}, err
+		}
+	}
+	if s.transcodingService.Enabled() {
+		// the stitched audio is already stored, so missing renditions only cost mobile clients the ladder
+		if stitched.Transcoded, err = s.transcodingService.Transcode(ctx, "prarthanas/"+name, file.Name()); err != nil {
+			log.Printf("Error transcoding stitched audio %s: %v\n", name, err)
+		}
+	}
+	return stitched, nil
+}
+
+func (s *AudioStitchingService) load(ctx context.Context, stotra entity.Stotra) (*util.PCM, error) {
+	key, ok := s.assetUrlService.PathFromUrl(stotra.StotraUrl)
+	if !ok {
+		return nil, fmt.Errorf("audio url %s is not on the asset storage", stotra.StotraUrl)
+	}
+	reader, err := s.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", key, err)
+	}
+	return util.DecodeAudio(data, path.Ext(key))
+}
+
+func (s *AudioStitchingService) upload(ctx context.Context, file *os.File, key string) error {
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("error reading stitched audio file: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error reading stitched audio file: %w", err)
+	}
+	if err := s.storage.Put(ctx, key, "audio/wav", file, info.Size()); err != nil {
+		return fmt.Errorf("error uploading stitched audio %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *AudioStitchingService) msToFrames(ms int) int {
+	return ms * s.config.SampleRate / 1000
+}
+
+func (s *AudioStitchingService) framesToMs(frames int) int {
+	return int(int64(frames) * 1000 / int64(s.config.SampleRate))
+}
@@ -0,0 +1,10 @@
+package audio_stitching
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type Service interface {
+	Stitch(ctx context.Context, name string, variant entity.Variant, stotras map[string]entity.Stotra) (entity.StitchedAudio, error)
+}
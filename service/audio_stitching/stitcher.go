@@ -0,0 +1,98 @@
+package audio_stitching
+
+import (
+	"fmt"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+)
+
+// stitcher streams clips into the output one at a time. The last crossfadeFrames of each clip
+// are held back so they can be mixed with the head of the next clip.
+type stitcher struct {
+	writer          *util.WavWriter
+	sampleRate      int
+	channels        int
+	crossfadeFrames int
+	written         int
+	tail            []int16
+	// waveform, when set, receives every written sample
+	waveform *util.WaveformBuilder
+}
+
+// add appends the clip, crossfading it into the held back tail of the previous clip if there
+// is one, and returns the frame at which the clip starts.
+func (st *stitcher) add(clip *util.PCM) (int, error) {
+	samples := clip.Samples
+	overlap := min(st.crossfadeFrames, len(st.tail)/st.channels, clip.Frames())
+	keep := len(st.tail) - overlap*st.channels
+	if err := st.write(st.tail[:keep]); err != nil {
+		return 0, err
+	}
+	start := st.written
+	if overlap > 0 {
+		mixed := make([]int16, overlap*st.channels)
+		for i := range mixed {
+			gain := float64(i/st.channels) / float64(overlap)
+			mixed[i] = int16(float64(st.tail[keep+i])*(1-gain) + float64(samples[i])*gain)
+		}
+		if err := st.write(mixed); err != nil {
+			return 0, err
+		}
+		samples = samples[len(mixed):]
+	}
+	st.tail = nil
+
+	held := min(st.crossfadeFrames*st.channels, len(samples))
+	if err := st.write(samples[:len(samples)-held]); err != nil {
+		return 0, err
+	}
+	st.tail = samples[len(samples)-held:]
+	return start, nil
+}
+
+// silence flushes the held back tail, so no crossfade happens across a gap, and writes the
+// given number of silent frames.
+func (st *stitcher) silence(frames int) error {
+	if frames <= 0 {
+		return nil
+	}
+	if err := st.flush(); err != nil {
+		return err
+	}
+	return st.write(make([]int16, frames*st.channels))
+}
+
+// end is the frame at which the audio added so far ends.
+func (st *stitcher) end() int {
+	return st.written + len(st.tail)/st.channels
+}
+
+func (st *stitcher) close() error {
+	if err := st.flush(); err != nil {
+		return err
+	}
+	if err := st.writer.Close(); err != nil {
+		return fmt.Errorf("error finalizing stitched audio: %w", err)
+	}
+	return nil
+}
+
+func (st *stitcher) flush() error {
+	tail := st.tail
+	st.tail = nil
+	return st.write(tail)
+}
+
+func (st *stitcher) write(samples []int16) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	if err := st.writer.Write(samples); err != nil {
+		return fmt.Errorf("error writing stitched audio: %w", err)
+	}
+	st.written += len(samples) / st.channels
+	if st.waveform != nil {
+		st.waveform.Add(samples)
+	}
+	return nil
+}
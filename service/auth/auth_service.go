@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+	bearerPrefix = "Bearer "
+
+	MethodApiKey = "api_key"
+	MethodJwt    = "jwt"
+)
+
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+type apiKey struct {
+	name string
+	hash []byte
+	role entity.Role
+}
+
+type AuthService struct {
+	logger     *zap.Logger
+	config     configuration.AuthConfig
+	apiKeys    []apiKey
+	keySet     *keySet
+	rolesClaim string
+}
+
+func InitAuthService(ctx context.Context, configuration *configuration.Configuration, httpClient *http.Client) (*AuthService, error) {
+	config := configuration.AuthConfig
+	var apiKeys []apiKey
+	for _, key := range config.ApiKeys {
+		hash, err := hex.DecodeString(key.KeyHash)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("api key %s: KeyHash must be a hex SHA-256 hash", key.Name)
+		}
+		role := entity.Role(strings.ToLower(key.Role))
+		if !role.Valid() {
+			return nil, fmt.Errorf("api key %s: unknown role %s", key.Name, key.Role)
+		}
+		apiKeys = append(apiKeys, apiKey{name: key.Name, hash: hash, role: role})
+	}
+	ttl := config.JwksTTL
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	rolesClaim := config.RolesClaim
+	if rolesClaim == "" {
+		rolesClaim = "roles"
+	}
+	return &AuthService{
+		logger:  logging.WithContext(ctx),
+		config:  config,
+		apiKeys: apiKeys,
+		keySet: &keySet{
+			url:        config.JwksUrl,
+			file:       config.JwksFile,
+			ttl:        ttl,
+			httpClient: httpClient,
+		},
+		rolesClaim: rolesClaim,
+	}, nil
+}
+
+// Authenticate identifies the caller by an X-API-Key header or a bearer JWT. Every failure
+// wraps ErrUnauthenticated; a caller without a known role gets a principal with no role.
+func (s *AuthService) Authenticate(ctx context.Context, req *http.Request) (*entity.Principal, error) {
+	if key := req.Header.Get(apiKeyHeader); key != "" {
+		return s.authenticateApiKey(key)
+	}
+	if header := req.Header.Get("Authorization"); strings.HasPrefix(header, bearerPrefix) {
+		return s.authenticateJwt(ctx, strings.TrimPrefix(header, bearerPrefix))
+	}
+	return nil, fmt.Errorf("%w: no credentials", ErrUnauthenticated)
+}
+
+func (s *AuthService) authenticateApiKey(key string) (*entity.Principal, error) {
+	hash := sha256.Sum256([]byte(key))
+	for _, candidate := range s.apiKeys {
+		if subtle.ConstantTimeCompare(hash[:], candidate.hash) == 1 {
+			return &entity.Principal{Subject: candidate.name, Role: candidate.role, Method: MethodApiKey}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown api key", ErrUnauthenticated)
+}
+
+func (s *AuthService) authenticateJwt(ctx context.Context, tokenString string) (*entity.Principal, error) {
+	options := []jwt.ParserOption{jwt.WithValidMethods(signingMethods), jwt.WithExpirationRequired()}
+	if s.config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(s.config.Issuer))
+	}
+	if s.config.Audience != "" {
+		options = append(options, jwt.WithAudience(s.config.Audience))
+	}
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return s.keySet.key(ctx, kid)
+	}, options...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrUnauthenticated)
+	}
+	return &entity.Principal{Subject: subject, Role: highestRole(claims[s.rolesClaim]), Method: MethodJwt}, nil
+}
+
+func highestRole(claim interface{}) entity.Role {
+	var values []string
+	switch v := claim.(type) {
+	case string:
+		values = strings.Fields(v)
+	case []interface{}:
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+	}
+	var highest entity.Role
+	for _, value := range values {
+		role := entity.Role(strings.ToLower(value))
+		if role.Valid() && role.Allows(highest) {
+			highest = role
+		}
+	}
+	return highest
+}
@@ -0,0 +1,11 @@
+package auth
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"net/http"
+)
+
+type Service interface {
+	Authenticate(ctx context.Context, req *http.Request) (*entity.Principal, error)
+}
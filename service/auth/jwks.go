@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// an unknown kid triggers a refetch at most this often, so forged tokens cannot hammer the issuer
+const minRefreshInterval = time.Minute
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keySet caches the signing keys of a JWKS document read from a URL or a local file.
+type keySet struct {
+	url        string
+	file       string
+	ttl        time.Duration
+	httpClient *http.Client
+	mu         sync.Mutex
+	keys       map[string]crypto.PublicKey
+	fetchedAt  time.Time
+}
+
+func (ks *keySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	key, ok := ks.keys[kid]
+	stale := time.Since(ks.fetchedAt) > ks.ttl
+	if ok && !stale {
+		return key, nil
+	}
+	if stale || time.Since(ks.fetchedAt) > minRefreshInterval {
+		keys, err := ks.fetch(ctx)
+		if err != nil {
+			if ok {
+				// keep serving a known key while the issuer is unreachable
+				return key, nil
+			}
+			return nil, err
+		}
+		ks.keys, ks.fetchedAt = keys, time.Now()
+	}
+	key, ok = ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func (ks *keySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	var data []byte
+	var err error
+	switch {
+	case ks.file != "":
+		data, err = os.ReadFile(ks.file)
+	case ks.url != "":
+		data, err = ks.download(ctx)
+	default:
+		return nil, fmt.Errorf("no JWKS configured")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading JWKS: %w", err)
+	}
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing JWKS: %w", err)
+	}
+	// issuers publish encryption and newer key types next to their signing keys; only the
+	// keys that cannot be used are skipped
+	keys := make(map[string]crypto.PublicKey)
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			log.Printf("Skipping JWKS key %q with use %s\n", k.Kid, k.Use)
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			log.Printf("Skipping JWKS key %q: %v\n", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no usable signing key in JWKS")
+	}
+	return keys, nil
+}
+
+func (ks *keySet) download(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ks.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d from %s", resp.StatusCode, ks.url)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
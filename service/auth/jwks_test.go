@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// the configuration package loads config.json from the working directory when the test
+// binary starts; the one next to this file is empty
+const testIssuer = "https://issuer.test"
+
+type testKeys struct {
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKeys{rsa: rsaKey, ec: ecKey}
+}
+
+func encode(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+// serveJwks serves the keys as a JWKS document, next to keys of a type and a use the service
+// cannot verify with, as issuers publish them.
+func serveJwks(t *testing.T, keys testKeys) *httptest.Server {
+	t.Helper()
+	doc := map[string][]map[string]string{"keys": {
+		{"kid": "rsa", "kty": "RSA", "use": "sig", "n": encode(keys.rsa.N), "e": encode(big.NewInt(int64(keys.rsa.E)))},
+		{"kid": "ec", "kty": "EC", "crv": "P-256", "x": encode(keys.ec.X), "y": encode(keys.ec.Y)},
+		{"kid": "okp", "kty": "OKP", "crv": "Ed25519", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+		{"kid": "enc", "kty": "RSA", "use": "enc", "n": encode(keys.rsa.N), "e": encode(big.NewInt(int64(keys.rsa.E)))},
+	}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(doc)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestService(t *testing.T, jwksUrl string) *AuthService {
+	t.Helper()
+	config := &configuration.Configuration{AuthConfig: configuration.AuthConfig{JwksUrl: jwksUrl, Issuer: testIssuer}}
+	service, err := InitAuthService(context.Background(), config, &http.Client{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func claims(roles interface{}, expiresAt time.Time) jwt.MapClaims {
+	return jwt.MapClaims{"sub": "someone", "iss": testIssuer, "exp": expiresAt.Unix(), "roles": roles}
+}
+
+func authenticate(service *AuthService, token string) (*entity.Principal, error) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", bearerPrefix+token)
+	return service.Authenticate(context.Background(), req)
+}
+
+func TestAuthenticateJwt(t *testing.T) {
+	keys := newTestKeys(t)
+	service := newTestService(t, serveJwks(t, keys).URL)
+	later := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		token string
+		role  entity.Role
+	}{
+		{"rsa key with the highest of its roles", sign(t, jwt.SigningMethodRS256, "rsa", keys.rsa, claims([]interface{}{"viewer", "editor"}, later)), entity.RoleEditor},
+		{"ec key with a space separated role claim", sign(t, jwt.SigningMethodES256, "ec", keys.ec, claims("viewer publisher", later)), entity.RolePublisher},
+		{"unknown roles grant nothing", sign(t, jwt.SigningMethodRS256, "rsa", keys.rsa, claims([]interface{}{"admin"}, later)), ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			principal, err := authenticate(service, test.token)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if principal.Subject != "someone" || principal.Method != MethodJwt || principal.Role != test.role {
+				t.Fatalf("got %+v, want role %q", principal, test.role)
+			}
+		})
+	}
+}
+
+func TestAuthenticateJwtRejects(t *testing.T) {
+	keys := newTestKeys(t)
+	service := newTestService(t, serveJwks(t, keys).URL)
+	later := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expired token", sign(t, jwt.SigningMethodRS256, "rsa", keys.rsa, claims("viewer", time.Now().Add(-time.Minute)))},
+		{"token without expiry", sign(t, jwt.SigningMethodRS256, "rsa", keys.rsa, jwt.MapClaims{"sub": "someone", "iss": testIssuer})},
+		{"wrong issuer", sign(t, jwt.SigningMethodRS256, "rsa", keys.rsa, jwt.MapClaims{"sub": "someone", "iss": "other", "exp": later.Unix()})},
+		{"hmac algorithm", sign(t, jwt.SigningMethodHS256, "rsa", []byte("secret"), claims("viewer", later))},
+		{"algorithm of another key", sign(t, jwt.SigningMethodES256, "rsa", keys.ec, claims("viewer", later))},
+		{"unknown kid", sign(t, jwt.SigningMethodRS256, "missing", keys.rsa, claims("viewer", later))},
+		{"encryption key", sign(t, jwt.SigningMethodRS256, "enc", keys.rsa, claims("viewer", later))},
+		{"unsupported key type", sign(t, jwt.SigningMethodRS256, "okp", keys.rsa, claims("viewer", later))},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := authenticate(service, test.token); !errors.Is(err, ErrUnauthenticated) {
+				t.Fatalf("got %v, want ErrUnauthenticated", err)
+			}
+		})
+	}
+}
+
+func TestFetchFailsWithoutUsableKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"keys":[{"kid":"okp","kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`))
+	}))
+	defer server.Close()
+	service := newTestService(t, server.URL)
+	if _, err := service.keySet.fetch(context.Background()); err == nil {
+		t.Fatal("expected an error for a JWKS without usable keys")
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role     entity.Role
+		required entity.Role
+		allowed  bool
+	}{
+		{entity.RolePublisher, entity.RoleEditor, true},
+		{entity.RoleEditor, entity.RoleEditor, true},
+		{entity.RoleViewer, entity.RoleEditor, false},
+		{"", entity.RoleViewer, false},
+	}
+	for _, test := range tests {
+		if got := test.role.Allows(test.required); got != test.allowed {
+			t.Errorf("%q.Allows(%q) = %v, want %v", test.role, test.required, got, test.allowed)
+		}
+	}
+}
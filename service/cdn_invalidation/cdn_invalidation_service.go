@@ -0,0 +1,224 @@
+package cdn_invalidation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/cdn"
+	cdnInvalidationRepo "github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/cdn_invalidation"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize  = 20
+	maxPageSize      = 100
+	defaultBatchSize = 1000
+)
+
+// CdnInvalidationService submits the paths of overwritten assets to the CDN in batches and
+// tracks each batch until the CDN reports it completed. Without a client, invalidation is
+// disabled and only stored batches can be read.
+type CdnInvalidationService struct {
+	logger     *zap.Logger
+	config     configuration.CdnConfig
+	repository cdnInvalidationRepo.MongoRepository
+	client     cdn.Client
+}
+
+func InitCdnInvalidationService(ctx context.Context,
+	configuration *configuration.Configuration,
+	repository cdnInvalidationRepo.MongoRepository,
+	client cdn.Client,
+) *CdnInvalidationService {
+	config := configuration.CdnConfig
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	return &CdnInvalidationService{
+		logger:     logging.WithContext(ctx),
+		config:     config,
+		repository: repository,
+		client:     client,
+	}
+}
+
+// Invalidate submits the paths, collapsed and split into batches, recording every batch
+// under the run id of the context. Batches the CDN rejects are stored as failed and
+// reported in the returned error; the others are still submitted.
+func (s *CdnInvalidationService) Invalidate(ctx context.Context, paths []string) ([]entity.CdnInvalidation, error) {
+	if s.client == nil {
+		return nil, entity.ErrCdnDisabled
+	}
+	for _, p := range paths {
+		if err := validatePath(p); err != nil {
+			return nil, err
+		}
+	}
+	var invalidations []entity.CdnInvalidation
+	var errs []error
+	collapsed := collapsePaths(paths, s.config.WildcardThreshold)
+	for start := 0; start < len(collapsed); start += s.config.BatchSize {
+		batch := collapsed[start:min(start+s.config.BatchSize, len(collapsed))]
+		now := time.Now().UTC()
+		invalidation := entity.CdnInvalidation{
+			Id:        uuid.NewString(),
+			RunId:     util.GetRunIdFromContext(ctx),
+			Paths:     batch,
+			Status:    entity.InvalidationPending,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := s.repository.Insert(ctx, invalidation); err != nil {
+			return invalidations, err
+		}
+		if err := s.submit(ctx, &invalidation); err != nil {
+			return invalidations, err
+		}
+		if invalidation.Status == entity.InvalidationFailed {
+			errs = append(errs, fmt.Errorf("invalidation %s failed: %s", invalidation.Id, invalidation.Error))
+		}
+		invalidations = append(invalidations, invalidation)
+	}
+	return invalidations, errors.Join(errs...)
+}
+
+// InvalidateChangedAssets invalidates the assets the audit trail of the context saw
+// overwritten and adds the invalidations to the trail.
+func (s *CdnInvalidationService) InvalidateChangedAssets(ctx context.Context) error {
+	trail := util.GetAuditTrailFromContext(ctx)
+	if s.client == nil || trail == nil {
+		return nil
+	}
+	keys := trail.ChangedAssets()
+	if len(keys) == 0 {
+		return nil
+	}
+	paths := make([]string, 0, len(keys))
+	for _, key := range keys {
+		paths = append(paths, "/"+strings.TrimPrefix(key, "/"))
+	}
+	invalidations, err := s.Invalidate(ctx, paths)
+	for _, invalidation := range invalidations {
+		trail.AddInvalidation(invalidation.Id)
+	}
+	return err
+}
+
+// Get returns the invalidation, first asking the CDN for the status of one in progress.
+func (s *CdnInvalidationService) Get(ctx context.Context, id string) (entity.CdnInvalidation, error) {
+	invalidation, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return entity.CdnInvalidation{}, err
+	}
+	if invalidation.Status != entity.InvalidationInProgress || s.client == nil {
+		return invalidation, nil
+	}
+	result, err := s.client.GetInvalidation(ctx, invalidation.CdnId)
+	if err != nil {
+		// the stored status is still the last known one
+		log.Printf("Error refreshing cdn invalidation %s: %v\n", id, err)
+		return invalidation, nil
+	}
+	if result.Status == invalidation.Status {
+		return invalidation, nil
+	}
+	applyStatus(&invalidation, result.Status)
+	if err = s.repository.Update(ctx, invalidation); err != nil {
+		return entity.CdnInvalidation{}, err
+	}
+	return invalidation, nil
+}
+
+func (s *CdnInvalidationService) List(ctx context.Context, query entity.CdnInvalidationQuery) (entity.CdnInvalidationPage, error) {
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = defaultPageSize
+	}
+	query.PageSize = min(query.PageSize, maxPageSize)
+	invalidations, total, err := s.repository.Find(ctx, query)
+	if err != nil {
+		return entity.CdnInvalidationPage{}, err
+	}
+	return entity.CdnInvalidationPage{
+		Items:    invalidations,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
+
+// submit sends a stored batch to the CDN and stores the outcome. The batch id is the caller
+// reference, so the CDN does not create a second invalidation for the same batch.
+func (s *CdnInvalidationService) submit(ctx context.Context, invalidation *entity.CdnInvalidation) error {
+	result, err := s.client.CreateInvalidation(ctx, invalidation.Id, invalidation.Paths)
+	if err != nil {
+		log.Printf("Error submitting cdn invalidation %s: %v\n", invalidation.Id, err)
+		invalidation.Status = entity.InvalidationFailed
+		invalidation.Error = err.Error()
+		invalidation.UpdatedAt = time.Now().UTC()
+	} else {
+		invalidation.CdnId = result.Id
+		applyStatus(invalidation, result.Status)
+	}
+	return s.repository.Update(ctx, *invalidation)
+}
+
+func applyStatus(invalidation *entity.CdnInvalidation, status string) {
+	now := time.Now().UTC()
+	invalidation.Status = status
+	invalidation.UpdatedAt = now
+	if status == entity.InvalidationCompleted {
+		invalidation.CompletedAt = &now
+	}
+}
+
+// validatePath accepts absolute paths, with "*" only as the last character as the CDN
+// requires.
+func validatePath(p string) error {
+	if !strings.HasPrefix(p, "/") || strings.Contains(strings.TrimSuffix(p, "*"), "*") {
+		return fmt.Errorf("%w: %q must start with / and may only end in *", entity.ErrInvalidCdnPath, p)
+	}
+	return nil
+}
+
+// collapsePaths removes duplicates and replaces the paths of a directory with "<dir>/*" once
+// there are more than threshold of them, since each path of a batch is charged for. A
+// threshold of zero keeps every path.
+func collapsePaths(paths []string, threshold int) []string {
+	unique := slices.Clone(paths)
+	slices.Sort(unique)
+	unique = slices.Compact(unique)
+	if threshold <= 0 {
+		return unique
+	}
+	byDir := make(map[string]int)
+	for _, p := range unique {
+		byDir[path.Dir(p)]++
+	}
+	collapsed := make([]string, 0, len(unique))
+	for _, p := range unique {
+		dir := path.Dir(p)
+		if byDir[dir] <= threshold {
+			collapsed = append(collapsed, p)
+			continue
+		}
+		wildcard := strings.TrimSuffix(dir, "/") + "/*"
+		if !slices.Contains(collapsed, wildcard) {
+			collapsed = append(collapsed, wildcard)
+		}
+	}
+	return collapsed
+}
@@ -0,0 +1,13 @@
+package cdn_invalidation
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type Service interface {
+	Invalidate(ctx context.Context, paths []string) ([]entity.CdnInvalidation, error)
+	InvalidateChangedAssets(ctx context.Context) error
+	Get(ctx context.Context, id string) (entity.CdnInvalidation, error)
+	List(ctx context.Context, query entity.CdnInvalidationQuery) (entity.CdnInvalidationPage, error)
+}
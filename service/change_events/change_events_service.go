@@ -0,0 +1,150 @@
+package change_events
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/broker"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/change_outbox"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/job_lease"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	relayLease      = "change_events:relay"
+)
+
+// ChangeEventService relays change events from the outbox to the broker. Every instance
+// polls, but only the one holding the relay lease publishes, so events leave in the order
+// they were written.
+type ChangeEventService struct {
+	logger          *zap.Logger
+	config          configuration.ChangeEventConfig
+	holder          string
+	outbox          change_outbox.MongoRepository
+	leaseRepository job_lease.MongoRepository
+	publisher       broker.Publisher
+}
+
+func InitChangeEventService(ctx context.Context,
+	configuration *configuration.Configuration,
+	outbox change_outbox.MongoRepository,
+	leaseRepository job_lease.MongoRepository,
+	publisher broker.Publisher,
+) *ChangeEventService {
+	hostname, _ := os.Hostname()
+	return &ChangeEventService{
+		logger:          logging.WithContext(ctx),
+		config:          configuration.ChangeEventConfig,
+		holder:          hostname + "-" + uuid.NewString(),
+		outbox:          outbox,
+		leaseRepository: leaseRepository,
+		publisher:       publisher,
+	}
+}
+
+// Start relays the outbox every PollInterval until the process exits.
+func (s *ChangeEventService) Start(ctx context.Context) error {
+	if s.config.PollInterval <= 0 || s.config.LeaseTTL <= s.config.PollInterval || s.config.BatchSize <= 0 {
+		return errors.New("change events need a positive poll interval and batch size and a lease TTL longer than the poll interval")
+	}
+	go func() {
+		ticker := time.NewTicker(s.config.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.relay(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// relay publishes the oldest pending events in order. It stops at an event waiting for a
+// retry or failing now, since publishing later events first would reorder them.
+func (s *ChangeEventService) relay(ctx context.Context) {
+	if !s.holdLease(ctx) {
+		return
+	}
+	entries, err := s.outbox.FindPending(ctx, s.config.BatchSize)
+	if err != nil {
+		log.Printf("Error reading the change outbox: %v\n", err)
+		return
+	}
+	for _, entry := range entries {
+		now := time.Now().UTC()
+		if entry.NextAttemptAt.After(now) {
+			return
+		}
+		if err := s.publisher.Publish(ctx, entry.Event); err != nil {
+			attempts := entry.Attempts + 1
+			log.Printf("Error publishing change event %s, attempt %d: %v\n", entry.Event.Id, attempts, err)
+			if err := s.outbox.MarkFailed(ctx, entry.Event.Id, attempts, err.Error(), now.Add(s.backoff(attempts))); err != nil {
+				log.Printf("Error updating change event %s: %v\n", entry.Event.Id, err)
+			}
+			return
+		}
+		if err := s.outbox.MarkPublished(ctx, entry.Event.Id, now); err != nil {
+			// the event goes out again on the next poll; consumers ignore versions they have seen
+			log.Printf("Error updating change event %s: %v\n", entry.Event.Id, err)
+			return
+		}
+	}
+}
+
+// holdLease renews the relay lease, or takes it over when it has expired.
+func (s *ChangeEventService) holdLease(ctx context.Context) bool {
+	held, err := s.leaseRepository.Renew(ctx, relayLease, s.holder, s.config.LeaseTTL)
+	if err == nil && !held {
+		held, err = s.leaseRepository.Acquire(ctx, relayLease, s.holder, s.config.LeaseTTL)
+	}
+	if err != nil {
+		log.Printf("Error holding the change event relay lease: %v\n", err)
+		return false
+	}
+	return held
+}
+
+// backoff doubles the poll interval with every failed attempt, up to MaxBackoff.
+func (s *ChangeEventService) backoff(attempts int) time.Duration {
+	delay := s.config.PollInterval
+	for i := 1; i < attempts && delay < s.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if s.config.MaxBackoff > 0 {
+		delay = min(delay, s.config.MaxBackoff)
+	}
+	return delay
+}
+
+func (s *ChangeEventService) List(ctx context.Context, query entity.OutboxQuery) (entity.OutboxPage, error) {
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = defaultPageSize
+	}
+	query.PageSize = min(query.PageSize, maxPageSize)
+	entries, total, err := s.outbox.Find(ctx, query)
+	if err != nil {
+		return entity.OutboxPage{}, err
+	}
+	return entity.OutboxPage{
+		Items:    entries,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
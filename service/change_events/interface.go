@@ -0,0 +1,11 @@
+package change_events
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type Service interface {
+	Start(ctx context.Context) error
+	List(ctx context.Context, query entity.OutboxQuery) (entity.OutboxPage, error)
+}
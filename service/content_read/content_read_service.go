@@ -0,0 +1,123 @@
+package content_read
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/prarthana_data"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var (
+	languagePattern = regexp.MustCompile(`^[a-z_]+$`)
+	fieldPattern    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z0-9_]+)*$`)
+)
+
+// supportedFilters lists the filters each content type can be listed by; language applies to all.
+var supportedFilters = map[string][]string{
+	entity.ContentShloks:     {},
+	entity.ContentStotras:    {},
+	entity.ContentPrarthanas: {"festival", "intent_based", "studio_recorded"},
+	entity.ContentDeities:    {"festival", "region"},
+}
+
+type ContentReadService struct {
+	logger     *zap.Logger
+	repository prarthana_data.MongoRepository
+}
+
+func InitContentReadService(ctx context.Context, repository prarthana_data.MongoRepository) *ContentReadService {
+	return &ContentReadService{
+		logger:     logging.WithContext(ctx),
+		repository: repository,
+	}
+}
+
+func (s *ContentReadService) List(ctx context.Context, contentType string, query entity.ContentQuery) (entity.ContentPage, error) {
+	if err := validateFilters(contentType, query); err != nil {
+		return entity.ContentPage{}, err
+	}
+	fields, err := parseFields(query.Fields)
+	if err != nil {
+		return entity.ContentPage{}, err
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = defaultPageSize
+	}
+	query.PageSize = min(query.PageSize, maxPageSize)
+	documents, total, err := s.repository.FindContent(ctx, contentType, query, fields)
+	if err != nil {
+		return entity.ContentPage{}, err
+	}
+	return entity.ContentPage{
+		Items:    documents,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
+
+func (s *ContentReadService) Get(ctx context.Context, contentType string, key string, fields string) (entity.ContentDocument, error) {
+	parsed, err := parseFields(fields)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.GetContentByKey(ctx, contentType, key, parsed)
+}
+
+func validateFilters(contentType string, query entity.ContentQuery) error {
+	supported, ok := supportedFilters[contentType]
+	if !ok {
+		return &entity.InvalidContentQueryError{Message: "unknown content type " + contentType}
+	}
+	if query.Language != "" && !languagePattern.MatchString(query.Language) {
+		return &entity.InvalidContentQueryError{Message: "invalid language " + query.Language}
+	}
+	used := map[string]bool{
+		"festival":        query.Festival != "",
+		"region":          query.Region != "",
+		"intent_based":    query.IntentBased != nil,
+		"studio_recorded": query.StudioRecorded != nil,
+	}
+	for filter, set := range used {
+		if set && !slices.Contains(supported, filter) {
+			return &entity.InvalidContentQueryError{Message: fmt.Sprintf("%s cannot be filtered by %s", contentType, filter)}
+		}
+	}
+	return nil
+}
+
+// parseFields splits the projection; paths nested in another requested path are rejected
+// since Mongo refuses colliding projections.
+func parseFields(fields string) ([]string, error) {
+	if strings.TrimSpace(fields) == "" {
+		return nil, nil
+	}
+	var parsed []string
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if !fieldPattern.MatchString(field) {
+			return nil, &entity.InvalidContentQueryError{Message: "invalid field " + field}
+		}
+		for _, other := range parsed {
+			if field == other || strings.HasPrefix(field, other+".") || strings.HasPrefix(other, field+".") {
+				return nil, &entity.InvalidContentQueryError{Message: fmt.Sprintf("fields %s and %s overlap", other, field)}
+			}
+		}
+		parsed = append(parsed, field)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,12 @@
+package content_read
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type Service interface {
+	List(ctx context.Context, contentType string, query entity.ContentQuery) (entity.ContentPage, error)
+	Get(ctx context.Context, contentType string, key string, fields string) (entity.ContentDocument, error)
+	ResolvePrarthana(ctx context.Context, key string, language string) (entity.ResolvedPrarthana, error)
+}
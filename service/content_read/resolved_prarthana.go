@@ -0,0 +1,176 @@
+package content_read
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+const defaultLanguage = "default"
+
+// ResolvePrarthana expands the prarthana's variants into chapters, stotras and shloks with
+// text in the language, falling back to the default text like the app does. Text missing in
+// the language and anything the app would render empty are reported in Problems instead of
+// failing the request.
+func (s *ContentReadService) ResolvePrarthana(ctx context.Context, key string, language string) (entity.ResolvedPrarthana, error) {
+	if language == "" {
+		language = defaultLanguage
+	}
+	if !languagePattern.MatchString(language) {
+		return entity.ResolvedPrarthana{}, &entity.InvalidContentQueryError{Message: "invalid language " + language}
+	}
+	content, err := s.repository.GetPrarthanaContent(ctx, key)
+	if err != nil {
+		return entity.ResolvedPrarthana{}, err
+	}
+	r := &resolver{
+		language: language,
+		stotras:  make(map[string]entity.Stotra),
+		shloks:   make(map[string]entity.Shlok),
+	}
+	for _, stotra := range content.Stotras {
+		r.stotras[stotra.ID] = stotra
+	}
+	for _, shlok := range content.Shloks {
+		r.shloks[shlok.ID] = shlok
+	}
+	return r.prarthana(content.Prarthana), nil
+}
+
+type resolver struct {
+	language string
+	stotras  map[string]entity.Stotra
+	shloks   map[string]entity.Shlok
+	problems []string
+}
+
+func (r *resolver) problem(format string, args ...interface{}) {
+	r.problems = append(r.problems, fmt.Sprintf(format, args...))
+}
+
+// text returns the value in the resolver's language or the default one, reporting the
+// field of the subject as a problem when the language has no value.
+func (r *resolver) text(subject string, field string, values map[string]string) string {
+	if value := values[r.language]; value != "" {
+		return value
+	}
+	fallback := values[defaultLanguage]
+	if fallback == "" && r.language != defaultLanguage {
+		r.problem("%s has no %s in %s or %s", subject, field, r.language, defaultLanguage)
+	} else {
+		r.problem("%s has no %s in %s", subject, field, r.language)
+	}
+	return fallback
+}
+
+func (r *resolver) prarthana(prarthana entity.Prarthana) entity.ResolvedPrarthana {
+	resolved := entity.ResolvedPrarthana{
+		Id:               prarthana.Id,
+		TmpId:            prarthana.TmpId,
+		Language:         r.language,
+		Title:            r.text("prarthana", "title", prarthana.Title),
+		AudioUrl:         prarthana.AudioInfo.AudioUrl,
+		HlsUrl:           prarthana.AudioInfo.HlsUrl,
+		IsStudioRecorded: prarthana.AudioInfo.IsStudioRecorded,
+		Variants:         []entity.ResolvedVariant{},
+	}
+	if prarthana.AudioInfo.IsAudioAvailable && resolved.AudioUrl == "" {
+		r.problem("prarthana is marked as having audio but has no audio url")
+	}
+	if len(prarthana.Variants) == 0 {
+		r.problem("prarthana has no variants")
+	}
+	for i, variant := range prarthana.Variants {
+		resolved.Variants = append(resolved.Variants, r.variant(i+1, variant))
+	}
+	resolved.Problems = r.problems
+	if resolved.Problems == nil {
+		resolved.Problems = []string{}
+	}
+	return resolved
+}
+
+func (r *resolver) variant(number int, variant entity.Variant) entity.ResolvedVariant {
+	resolved := entity.ResolvedVariant{
+		IsDefault: variant.IsDefault,
+		Duration:  variant.Duration,
+		Chapters:  []entity.ResolvedChapter{},
+	}
+	if len(variant.Chapters) == 0 {
+		r.problem("variant %d has no chapters", number)
+	}
+	for _, chapter := range variant.Chapters {
+		resolvedChapter := r.chapter(fmt.Sprintf("variant %d chapter %d", number, chapter.Order), chapter)
+		resolved.DurationMs += resolvedChapter.DurationMs
+		resolved.Chapters = append(resolved.Chapters, resolvedChapter)
+	}
+	return resolved
+}
+
+func (r *resolver) chapter(path string, chapter entity.Chapter) entity.ResolvedChapter {
+	resolved := entity.ResolvedChapter{
+		Order:   chapter.Order,
+		Title:   r.text(path, "title", chapter.Title),
+		StartMs: chapter.StartMs,
+		Stotras: []entity.ResolvedStotra{},
+	}
+	if len(chapter.StotraIds) == 0 {
+		r.problem("%s has no stotras", path)
+	}
+	sum := 0
+	for _, stotraId := range chapter.StotraIds {
+		stotra := r.stotra(path, stotraId)
+		sum += stotra.DurationMs
+		resolved.Stotras = append(resolved.Stotras, stotra)
+	}
+	resolved.DurationMs = chapter.DurationMs
+	if resolved.DurationMs == 0 {
+		resolved.DurationMs = sum
+	}
+	return resolved
+}
+
+func (r *resolver) stotra(path string, stotraId string) entity.ResolvedStotra {
+	stotra, ok := r.stotras[stotraId]
+	if !ok {
+		r.problem("%s references missing stotra %s", path, stotraId)
+		return entity.ResolvedStotra{Id: stotraId, Missing: true, Shloks: []entity.ResolvedShlok{}}
+	}
+	resolved := entity.ResolvedStotra{
+		Id:         stotra.ID,
+		Title:      r.text("stotra "+stotraId, "title", stotra.Title),
+		AudioUrl:   stotra.StotraUrl,
+		HlsUrl:     stotra.HlsUrl,
+		DurationMs: stotra.DurationInMilliseconds,
+		Shloks:     []entity.ResolvedShlok{},
+	}
+	if resolved.AudioUrl == "" {
+		r.problem("stotra %s has no audio", stotraId)
+	}
+	timings := make(map[string]entity.ShlokTiming)
+	for _, timing := range stotra.ShlokTimings {
+		timings[timing.ShlokId] = timing
+	}
+	for _, shlokId := range stotra.ShlokIds {
+		resolved.Shloks = append(resolved.Shloks, r.shlok(stotraId, shlokId, timings[shlokId]))
+	}
+	return resolved
+}
+
+func (r *resolver) shlok(stotraId string, shlokId string, timing entity.ShlokTiming) entity.ResolvedShlok {
+	shlok, ok := r.shloks[shlokId]
+	if !ok {
+		r.problem("stotra %s references missing shlok %s", stotraId, shlokId)
+		return entity.ResolvedShlok{Id: shlokId, Missing: true}
+	}
+	resolved := entity.ResolvedShlok{
+		Id:          shlok.ID,
+		Title:       r.text("shlok "+shlokId, "title", shlok.Title),
+		Text:        r.text("shlok "+shlokId, "text", shlok.Shlok),
+		Explanation: r.text("shlok "+shlokId, "explanation", shlok.Explanation),
+		StartMs:     timing.StartMs,
+		EndMs:       timing.EndMs,
+	}
+	return resolved
+}
@@ -6,12 +6,17 @@ import (
 	"fmt"
 	"log"
 	"regexp"
-	"strconv"
 	"strings"
 
 	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
 	mongoRepo "github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/prarthana_data"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/storage"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_url"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_verifier"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/deity_prarthana_link"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/search_indexing"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/zoho"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
 	"github.com/google/uuid"
@@ -19,27 +24,48 @@ import (
 )
 
 type DeityIngestionService struct {
-	logger                   *zap.Logger
-	prarthanaMongoRepository mongoRepo.MongoRepository
-	zohoService              zoho.Service
+	logger                    *zap.Logger
+	prarthanaMongoRepository  mongoRepo.MongoRepository
+	zohoService               zoho.Service
+	searchIndexingService     search_indexing.Service
+	deityPrarthanaLinkService deity_prarthana_link.Service
+	assetUrlService           asset_url.Service
+	assetVerifierService      asset_verifier.Service
+	assetStorage              storage.Storage
+	requireDodImage           bool
 }
 
 func InitDeityIngestionService(ctx context.Context,
+	configuration *configuration.Configuration,
 	prarthanaMongoRepository mongoRepo.MongoRepository,
 	zohoService zoho.Service,
+	searchIndexingService search_indexing.Service,
+	deityPrarthanaLinkService deity_prarthana_link.Service,
+	assetUrlService asset_url.Service,
+	assetVerifierService asset_verifier.Service,
+	assetStorage storage.Storage,
 ) *DeityIngestionService {
 	return &DeityIngestionService{
-		logger:                   logging.WithContext(ctx),
-		prarthanaMongoRepository: prarthanaMongoRepository,
-		zohoService:              zohoService,
+		logger:                    logging.WithContext(ctx),
+		prarthanaMongoRepository:  prarthanaMongoRepository,
+		zohoService:               zohoService,
+		searchIndexingService:     searchIndexingService,
+		deityPrarthanaLinkService: deityPrarthanaLinkService,
+		assetUrlService:           assetUrlService,
+		assetVerifierService:      assetVerifierService,
+		assetStorage:              assetStorage,
+		requireDodImage:           configuration.DeityConfig.RequireDodImage,
 	}
 }
 
-func (s *DeityIngestionService) DeityIngestion(ctx context.Context, startID, endID int) (map[string]string, error) {
+// DeityIngestion ingests the deities with IDs in range. The source hash of a deity covers its
+// row, its prarthanas and the hero images stored for it; incrementally, deities whose hash
+// matches the stored one are skipped.
+func (s *DeityIngestionService) DeityIngestion(ctx context.Context, startID, endID int, incremental bool) (map[string]string, error) {
 	var err error
-	_, deityToPrarthanaMap, err := s.preparePrarthanaToDeityMap(ctx)
+	mapping, err := s.deityPrarthanaLinkService.GetMapping(ctx)
 	if err != nil {
-		log.Fatalf("Error generating Prarthana TmpId to Deity ID map: %v", err)
+		return nil, err
 	}
 	prarthanaIdMap, err := s.prarthanaMongoRepository.GeneratePrarthanaTmpIdToIdMap(ctx)
 	if err != nil {
@@ -56,11 +82,22 @@ func (s *DeityIngestionService) DeityIngestion(ctx context.Context, startID, end
 
 	var deities []entity.DeityDocument
 	deityIdMap := make(map[string]string)
+	var assetChecks []entity.AssetCheck
 
 	tmpIdToDeityIdMap, err := s.prarthanaMongoRepository.GetTmpIdToDeityIdMap(ctx)
 	if err != nil {
 		log.Fatal(err)
 	}
+	heroAlbums, err := s.loadHeroAlbumCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var storedHashes map[string]string
+	if incremental {
+		if storedHashes, err = s.prarthanaMongoRepository.GetSourceHashes(ctx, entity.ContentDeities); err != nil {
+			return nil, err
+		}
+	}
 	for i, record := range response.Records {
 		log.Printf("Processing record %d\n", i+1)
 
@@ -74,6 +111,18 @@ func (s *DeityIngestionService) DeityIngestion(ctx context.Context, startID, end
 			continue
 		}
 		deityNameDefault := record["Title (Default)"].(string)
+		tmpId := fmt.Sprintf("%d", id)
+		formattedtitle := strings.ToLower(strings.ReplaceAll(deityNameDefault, " ", "_"))
+		sourceHash := util.SourceHash(record,
+			mapping.DeityToPrarthanas[tmpId],
+			heroAlbums.heroIndexes(formattedtitle),
+			heroAlbums.has(asset_url.DodImage, formattedtitle),
+		)
+		if incremental && storedHashes[tmpId] == sourceHash {
+			log.Printf("Skipping unchanged deity %d\n", id)
+			util.RecordChange(ctx, entity.ContentDeities, tmpId, entity.ChangeSkipped, nil)
+			continue
+		}
 		re := regexp.MustCompile(`[^a-zA-Z0-9\s]+`)
 		if re.MatchString(deityNameDefault) {
 			return nil, fmt.Errorf("the name '%s' contains special characters. Please remove them", deityNameDefault)
@@ -94,7 +143,6 @@ func (s *DeityIngestionService) DeityIngestion(ctx context.Context, startID, end
 		if strings.TrimSpace(deityUuid) == "" {
 			deityUuid = uuid.NewString()
 		}
-		tmpId := fmt.Sprintf("%d", id)
 		if val, found := tmpIdToDeityIdMap[tmpId]; found {
 			deityUuid = val
 		}
@@ -102,34 +150,44 @@ func (s *DeityIngestionService) DeityIngestion(ctx context.Context, startID, end
 		if !ok {
 			return nil, errors.New("Invalid Deity Image")
 		}
-		defaultImage := fmt.Sprintf("https://d161fa2zahtt3z.cloudfront.net/prarthanas/deities/list-image/%s.png", deityImageName)
-		if !util.UrlExists(defaultImage) {
-			return nil, fmt.Errorf("deity image does not exist: %s", defaultImage)
+		defaultImage := s.assetUrlService.Url(asset_url.DeityListImage, deityImageName)
+		backgroundImage := s.assetUrlService.Url(asset_url.DeityBgImage, deityImageName)
+		assetChecks = append(assetChecks,
+			entity.AssetCheck{Row: id, Kind: asset_url.DeityListImage, Candidates: []string{defaultImage}},
+			entity.AssetCheck{Row: id, Kind: asset_url.DeityBgImage, Candidates: []string{backgroundImage}},
+		)
+		// the album follows the full images actually stored; the sheet's count is only a cross-check
+		var heroImageAlbum []entity.HeroImageAlbum
+		heroIndexes := heroAlbums.heroIndexes(formattedtitle)
+		if missing := missingHeroIndexes(heroIndexes); len(missing) > 0 {
+			util.RecordWarning(ctx, entity.ContentDeities, "row %d: hero images of %s are missing indexes %v", id, formattedtitle, missing)
 		}
-		backgroundImage := fmt.Sprintf("https://d161fa2zahtt3z.cloudfront.net/prarthanas/deities/bg-image/%s.png", deityImageName)
-		if !util.UrlExists(backgroundImage) {
-			return nil, fmt.Errorf("deity background image does not exist: %s", backgroundImage)
+		if heroImageCount, ok := record["Hero Image Count"].(float64); ok && int(heroImageCount) != len(heroIndexes) {
+			util.RecordWarning(ctx, entity.ContentDeities, "row %d: Hero Image Count is %d but %d hero images of %s are stored", id, int(heroImageCount), len(heroIndexes), formattedtitle)
 		}
-		formattedtitle := strings.ToLower(strings.ReplaceAll(deityNameDefault, " ", "_"))
-		var heroImageAlbum []entity.HeroImageAlbum
-		heroImageCount, ok := record["Hero Image Count"].(float64)
-		if ok && heroImageCount > 0 {
-			for i := 0; i < int(heroImageCount); i++ { // Convert float64 to int directly
-				imageIndex := ""
-				if i > 0 {
-					imageIndex = strconv.Itoa(i)
-				}
-				heroImageAlbum = append(heroImageAlbum, entity.HeroImageAlbum{
-					FullImage:      fmt.Sprintf("https://d161fa2zahtt3z.cloudfront.net/prarthanas/deities/hero_image_album/full_image/%s%s.png", formattedtitle, imageIndex),
-					ThumbnailImage: fmt.Sprintf("https://d161fa2zahtt3z.cloudfront.net/prarthanas/deities/hero_image_album/full_image/%s%s.png", formattedtitle, imageIndex),
-					ShareImage:     fmt.Sprintf("https://d161fa2zahtt3z.cloudfront.net/prarthanas/deities/hero_image_album/share_image/%s%s.png", formattedtitle, imageIndex),
-				})
+		for _, index := range heroIndexes {
+			name := heroImageName(formattedtitle, index)
+			album := entity.HeroImageAlbum{
+				FullImage:      s.assetUrlService.Url(asset_url.HeroFullImage, name),
+				ThumbnailImage: s.assetUrlService.Url(asset_url.HeroThumbnailImage, name),
+				ShareImage:     s.assetUrlService.Url(asset_url.HeroShareImage, name),
 			}
+			heroImageAlbum = append(heroImageAlbum, album)
+			assetChecks = append(assetChecks,
+				entity.AssetCheck{Row: id, Kind: asset_url.HeroFullImage, Candidates: []string{album.FullImage}},
+				entity.AssetCheck{Row: id, Kind: asset_url.HeroThumbnailImage, Candidates: []string{album.ThumbnailImage}},
+				entity.AssetCheck{Row: id, Kind: asset_url.HeroShareImage, Candidates: []string{album.ShareImage}},
+			)
 		}
 
 		var deityOfTheDay string
 		if dodFlag, ok := record["DOD Flag"].(bool); ok && dodFlag {
-			deityOfTheDay = fmt.Sprintf("https://d161fa2zahtt3z.cloudfront.net/prarthanas/deities/hero_image_album/dod_image/%s.png", formattedtitle)
+			if heroAlbums.has(asset_url.DodImage, formattedtitle) || s.requireDodImage {
+				deityOfTheDay = s.assetUrlService.Url(asset_url.DodImage, formattedtitle)
+				assetChecks = append(assetChecks, entity.AssetCheck{Row: id, Kind: asset_url.DodImage, Candidates: []string{deityOfTheDay}})
+			} else {
+				util.RecordWarning(ctx, entity.ContentDeities, "row %d: DOD Flag is set but no DOD image of %s is stored", id, formattedtitle)
+			}
 		}
 
 		aliases, ok := record["Also known as"].(string)
@@ -223,51 +281,43 @@ func (s *DeityIngestionService) DeityIngestion(ctx context.Context, startID, end
 				DeityOfTheDay:   deityOfTheDay,
 			},
 			FestivalIds: festivalIds,
+			SourceHash:  sourceHash,
 		}
+		deity.SearchKeywords = util.NewKeywordBuilder().
+			AddMap(deity.Title).
+			Add(deity.Aliases...).
+			AddListMap(deity.AliasesV1).
+			Add(deity.Region...).
+			Add(deity.FestivalIds...).
+			Keywords()
 		deityIdMap[tmpId] = deity.Id
 		deities = append(deities, deity)
 	}
+	if _, missingErr := s.assetVerifierService.Verify(ctx, "deity", assetChecks); missingErr != nil {
+		return nil, missingErr
+	}
 	for i, deity := range deities {
-		ids := deityToPrarthanaMap[deity.TmpId]
-		var prarthanaIds []string
+		ids := mapping.DeityToPrarthanas[deity.TmpId]
+		prarthanaIds := []string{}
 		for _, id := range ids {
-			prarthanaIds = append(prarthanaIds, prarthanaIdMap[id])
+			if prarthanaId, ok := prarthanaIdMap[id]; ok {
+				prarthanaIds = append(prarthanaIds, prarthanaId)
+			}
 		}
 		deities[i].Prarthanas = prarthanaIds
 	}
-	return deityIdMap, s.prarthanaMongoRepository.InsertManyDeities(ctx, deities)
-}
-
-func (s *DeityIngestionService) preparePrarthanaToDeityMap(ctx context.Context) (map[string]string, map[string][]string, error) {
-	var response entity.ShlokaSheetResponse
-	err := s.zohoService.GetSheetData(ctx, "deity to prarthana mapping", &response)
-	if err != nil {
-		return nil, nil, err
+	if err := s.prarthanaMongoRepository.InsertManyDeities(ctx, deities); err != nil {
+		return nil, err
 	}
-	if len(response.Records) == 0 {
-		return nil, nil, errors.New("no records found")
+	if err := s.deityPrarthanaLinkService.SyncLinks(ctx, mapping); err != nil {
+		return nil, err
 	}
-	pdmap := make(map[string]string)
-	dpMap := make(map[string][]string)
-	for _, record := range response.Records {
-		prarthanaIdf, ok := record["Prarthana ID"].(float64)
-		if !ok {
-			return nil, nil, errors.New("prarthana ID is not a float")
-		}
-		deityIdString := fmt.Sprintf("%v", record["Diety ID"])
-
-		//deityIdf, ok := record["Diety ID"].(float64)
-		if len(deityIdString) == 0 {
-			return nil, nil, errors.New("diety ID is not a float")
-		}
-		deityIds := util.GetSplittedString(deityIdString)
-		prarthanaId := strconv.FormatFloat(prarthanaIdf, 'f', -1, 64)
-		//deityId := fmt.Sprintf("%f", deityIdf)
-		for _, id := range deityIds {
-			pdmap[prarthanaId] = id
-			dpMap[id] = append(dpMap[id], prarthanaId)
-		}
-
+	tmpIds := make([]string, 0, len(deityIdMap))
+	for tmpId := range deityIdMap {
+		tmpIds = append(tmpIds, tmpId)
+	}
+	if err := s.searchIndexingService.IndexDeities(ctx, tmpIds); err != nil {
+		log.Printf("Failed to index deities for search: %v\n", err)
 	}
-	return pdmap, dpMap, nil
+	return deityIdMap, nil
 }
@@ -0,0 +1,88 @@
+package deity_ingestion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_url"
+)
+
+var heroAlbumKinds = []string{
+	asset_url.HeroFullImage,
+	asset_url.HeroThumbnailImage,
+	asset_url.HeroShareImage,
+	asset_url.DodImage,
+}
+
+// heroAlbumCatalog holds the asset names present on the storage backend per hero album kind,
+// listed once per ingestion instead of once per deity.
+type heroAlbumCatalog map[string]map[string]bool
+
+func (s *DeityIngestionService) loadHeroAlbumCatalog(ctx context.Context) (heroAlbumCatalog, error) {
+	catalog := make(heroAlbumCatalog)
+	for _, kind := range heroAlbumKinds {
+		keys, err := s.assetStorage.List(ctx, s.assetUrlService.Prefix(kind))
+		if err != nil {
+			return nil, fmt.Errorf("error listing %s assets: %w", kind, err)
+		}
+		names := make(map[string]bool)
+		for _, key := range keys {
+			if name, ok := s.assetUrlService.NameFromPath(kind, key); ok {
+				names[name] = true
+			}
+		}
+		catalog[kind] = names
+	}
+	return catalog, nil
+}
+
+func (c heroAlbumCatalog) has(kind string, name string) bool {
+	return c[kind][name]
+}
+
+// heroIndexes returns the sorted album indexes of the full images stored for a deity. Index 0
+// is the image named after the deity itself, index n the one suffixed with n.
+func (c heroAlbumCatalog) heroIndexes(title string) []int {
+	var indexes []int
+	for name := range c[asset_url.HeroFullImage] {
+		suffix, ok := strings.CutPrefix(name, title)
+		if !ok {
+			continue
+		}
+		if suffix == "" {
+			indexes = append(indexes, 0)
+			continue
+		}
+		if strings.HasPrefix(suffix, "0") {
+			continue
+		}
+		if index, err := strconv.Atoi(suffix); err == nil && index > 0 {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+func heroImageName(title string, index int) string {
+	if index == 0 {
+		return title
+	}
+	return title + strconv.Itoa(index)
+}
+
+// missingHeroIndexes returns the gaps in the numbering, e.g. [2] for images 0, 1 and 3.
+func missingHeroIndexes(indexes []int) []int {
+	var missing []int
+	next := 0
+	for _, index := range indexes {
+		for ; next < index; next++ {
+			missing = append(missing, next)
+		}
+		next = index + 1
+	}
+	return missing
+}
@@ -5,5 +5,5 @@ import (
 )
 
 type Service interface {
-	DeityIngestion(ctx context.Context, startID, endID int) (map[string]string, error)
+	DeityIngestion(ctx context.Context, startID, endID int, incremental bool) (map[string]string, error)
 }
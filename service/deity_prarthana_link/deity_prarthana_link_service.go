@@ -0,0 +1,164 @@
+package deity_prarthana_link
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	mongoRepo "github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/prarthana_data"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/zoho"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+	"go.uber.org/zap"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const mappingSheet = "deity to prarthana mapping"
+
+type DeityPrarthanaLinkService struct {
+	logger                   *zap.Logger
+	prarthanaMongoRepository mongoRepo.MongoRepository
+	zohoService              zoho.Service
+}
+
+func InitDeityPrarthanaLinkService(ctx context.Context,
+	prarthanaMongoRepository mongoRepo.MongoRepository,
+	zohoService zoho.Service,
+) *DeityPrarthanaLinkService {
+	return &DeityPrarthanaLinkService{
+		logger:                   logging.WithContext(ctx),
+		prarthanaMongoRepository: prarthanaMongoRepository,
+		zohoService:              zohoService,
+	}
+}
+
+// GetMapping reads the mapping sheet. Prarthanas within a deity are ordered by the
+// "Order" column, falling back to sheet row order for rows without one.
+func (s *DeityPrarthanaLinkService) GetMapping(ctx context.Context) (entity.DeityPrarthanaMapping, error) {
+	var response entity.ShlokaSheetResponse
+	err := s.zohoService.GetSheetData(ctx, mappingSheet, &response)
+	if err != nil {
+		return entity.DeityPrarthanaMapping{}, err
+	}
+	if len(response.Records) == 0 {
+		return entity.DeityPrarthanaMapping{}, errors.New("no records found")
+	}
+
+	type orderedPrarthana struct {
+		prarthanaId string
+		order       float64
+		row         int
+	}
+	deityRows := make(map[string][]orderedPrarthana)
+	prarthanaToDeities := make(map[string][]string)
+	for i, record := range response.Records {
+		prarthanaIdf, ok := record["Prarthana ID"].(float64)
+		if !ok {
+			return entity.DeityPrarthanaMapping{}, fmt.Errorf("prarthana ID is not a number in mapping row %d", i+1)
+		}
+		prarthanaId := strconv.FormatFloat(prarthanaIdf, 'f', -1, 64)
+		deityIds := util.GetSplittedString(fmt.Sprintf("%v", record["Diety ID"]))
+		if len(deityIds) == 0 {
+			return entity.DeityPrarthanaMapping{}, fmt.Errorf("diety ID missing in mapping row %d", i+1)
+		}
+		order := math.MaxFloat64
+		if orderf, ok := record["Order"].(float64); ok {
+			order = orderf
+		}
+		for _, deityId := range deityIds {
+			if slices.Contains(prarthanaToDeities[prarthanaId], deityId) {
+				continue
+			}
+			prarthanaToDeities[prarthanaId] = append(prarthanaToDeities[prarthanaId], deityId)
+			deityRows[deityId] = append(deityRows[deityId], orderedPrarthana{prarthanaId: prarthanaId, order: order, row: i})
+		}
+	}
+
+	deityToPrarthanas := make(map[string][]string)
+	for deityId, rows := range deityRows {
+		sort.SliceStable(rows, func(i, j int) bool {
+			if rows[i].order != rows[j].order {
+				return rows[i].order < rows[j].order
+			}
+			return rows[i].row < rows[j].row
+		})
+		for _, row := range rows {
+			deityToPrarthanas[deityId] = append(deityToPrarthanas[deityId], row.prarthanaId)
+		}
+	}
+	return entity.DeityPrarthanaMapping{
+		DeityToPrarthanas:  deityToPrarthanas,
+		PrarthanaToDeities: prarthanaToDeities,
+	}, nil
+}
+
+// SyncLinks writes the mapping onto both deities.prarthanas and prarthanas.deity_ids for
+// every stored document, clearing links that are no longer in the sheet. The caller passes
+// the mapping it hashed, so links and source hashes come from the same read. Deities and
+// prarthanas the sheet references but that are not ingested yet are reported as warnings
+// and left out, so either content type can be ingested first; their links are written by the
+// sync after they are ingested.
+func (s *DeityPrarthanaLinkService) SyncLinks(ctx context.Context, mapping entity.DeityPrarthanaMapping) error {
+	deityIdMap, err := s.prarthanaMongoRepository.GenerateDeityTmpIdToIdMap(ctx)
+	if err != nil {
+		return err
+	}
+	prarthanaIdMap, err := s.prarthanaMongoRepository.GeneratePrarthanaTmpIdToIdMap(ctx)
+	if err != nil {
+		return err
+	}
+
+	var missingDeities, missingPrarthanas []string
+	for deityTmpId := range mapping.DeityToPrarthanas {
+		if _, ok := deityIdMap[deityTmpId]; !ok {
+			missingDeities = append(missingDeities, deityTmpId)
+		}
+	}
+	for prarthanaTmpId := range mapping.PrarthanaToDeities {
+		if _, ok := prarthanaIdMap[prarthanaTmpId]; !ok {
+			missingPrarthanas = append(missingPrarthanas, prarthanaTmpId)
+		}
+	}
+	if len(missingDeities) > 0 {
+		sort.Strings(missingDeities)
+		util.RecordWarning(ctx, entity.ContentDeities, "Deity to prarthana mapping references deities not ingested yet, not linked: %s", strings.Join(missingDeities, ", "))
+	}
+	if len(missingPrarthanas) > 0 {
+		sort.Strings(missingPrarthanas)
+		util.RecordWarning(ctx, entity.ContentPrarthanas, "Deity to prarthana mapping references prarthanas not ingested yet, not linked: %s", strings.Join(missingPrarthanas, ", "))
+	}
+
+	deityLinks := make(map[string][]string)
+	for deityTmpId, prarthanaTmpIds := range mapping.DeityToPrarthanas {
+		if deityId, ok := deityIdMap[deityTmpId]; ok {
+			deityLinks[deityId] = resolveIds(prarthanaTmpIds, prarthanaIdMap)
+		}
+	}
+	prarthanaLinks := make(map[string][]string)
+	for prarthanaTmpId, deityTmpIds := range mapping.PrarthanaToDeities {
+		if prarthanaId, ok := prarthanaIdMap[prarthanaTmpId]; ok {
+			prarthanaLinks[prarthanaId] = resolveIds(deityTmpIds, deityIdMap)
+		}
+	}
+	if err := s.prarthanaMongoRepository.UpdateDeityPrarthanaLinks(ctx, deityLinks, prarthanaLinks); err != nil {
+		return err
+	}
+	log.Printf("Synced deity to prarthana links for %d deities and %d prarthanas\n", len(deityLinks), len(prarthanaLinks))
+	return nil
+}
+
+// resolveIds maps TmpIds to _ids, skipping the ones not ingested.
+func resolveIds(tmpIds []string, idMap map[string]string) []string {
+	ids := make([]string, 0, len(tmpIds))
+	for _, tmpId := range tmpIds {
+		if id, ok := idMap[tmpId]; ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,11 @@
+package deity_prarthana_link
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type Service interface {
+	GetMapping(ctx context.Context) (entity.DeityPrarthanaMapping, error)
+	SyncLinks(ctx context.Context, mapping entity.DeityPrarthanaMapping) error
+}
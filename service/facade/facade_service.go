@@ -4,8 +4,16 @@ import (
 	"context"
 	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_upload"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/cdn_invalidation"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/change_events"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/content_read"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/deity_ingestion"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/ingestion_audit"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/notifier"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/prarthana_ingestion"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/search_indexing"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/sheet_snapshot"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/shlok_ingestion"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/stotra_ingestion"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/zoho"
@@ -20,6 +28,14 @@ type FacadeService struct {
 	prarthanaIngestionService prarthana_ingestion.Service
 	deityIngestionService     deity_ingestion.Service
 	zohoAuthService           zoho.Service
+	searchIndexingService     search_indexing.Service
+	assetUploadService        asset_upload.Service
+	ingestionAuditService     ingestion_audit.Service
+	contentReadService        content_read.Service
+	sheetSnapshotService      sheet_snapshot.Service
+	notifierService           notifier.Service
+	changeEventService        change_events.Service
+	cdnInvalidationService    cdn_invalidation.Service
 }
 
 func InitFacadeService(
@@ -30,6 +46,14 @@ func InitFacadeService(
 	prarthanaIngestionService prarthana_ingestion.Service,
 	deityIngestionService deity_ingestion.Service,
 	zohoAuthService zoho.Service,
+	searchIndexingService search_indexing.Service,
+	assetUploadService asset_upload.Service,
+	ingestionAuditService ingestion_audit.Service,
+	contentReadService content_read.Service,
+	sheetSnapshotService sheet_snapshot.Service,
+	notifierService notifier.Service,
+	changeEventService change_events.Service,
+	cdnInvalidationService cdn_invalidation.Service,
 
 ) *FacadeService {
 	return &FacadeService{
@@ -40,6 +64,14 @@ func InitFacadeService(
 		prarthanaIngestionService: prarthanaIngestionService,
 		deityIngestionService:     deityIngestionService,
 		zohoAuthService:           zohoAuthService,
+		searchIndexingService:     searchIndexingService,
+		assetUploadService:        assetUploadService,
+		ingestionAuditService:     ingestionAuditService,
+		contentReadService:        contentReadService,
+		sheetSnapshotService:      sheetSnapshotService,
+		notifierService:           notifierService,
+		changeEventService:        changeEventService,
+		cdnInvalidationService:    cdnInvalidationService,
 	}
 }
 
@@ -62,3 +94,35 @@ func (s *FacadeService) DeityIngestionService() deity_ingestion.Service {
 func (s *FacadeService) ZohoAuthService() zoho.Service {
 	return s.zohoAuthService
 }
+
+func (s *FacadeService) SearchIndexingService() search_indexing.Service {
+	return s.searchIndexingService
+}
+
+func (s *FacadeService) AssetUploadService() asset_upload.Service {
+	return s.assetUploadService
+}
+
+func (s *FacadeService) IngestionAuditService() ingestion_audit.Service {
+	return s.ingestionAuditService
+}
+
+func (s *FacadeService) ContentReadService() content_read.Service {
+	return s.contentReadService
+}
+
+func (s *FacadeService) SheetSnapshotService() sheet_snapshot.Service {
+	return s.sheetSnapshotService
+}
+
+func (s *FacadeService) NotifierService() notifier.Service {
+	return s.notifierService
+}
+
+func (s *FacadeService) ChangeEventService() change_events.Service {
+	return s.changeEventService
+}
+
+func (s *FacadeService) CdnInvalidationService() cdn_invalidation.Service {
+	return s.cdnInvalidationService
+}
@@ -1,8 +1,16 @@
 package facade
 
 import (
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_upload"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/cdn_invalidation"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/change_events"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/content_read"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/deity_ingestion"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/ingestion_audit"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/notifier"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/prarthana_ingestion"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/search_indexing"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/sheet_snapshot"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/shlok_ingestion"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/stotra_ingestion"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/zoho"
@@ -14,4 +22,12 @@ type Service interface {
 	PrarthanaIngestionService() prarthana_ingestion.Service
 	DeityIngestionService() deity_ingestion.Service
 	ZohoAuthService() zoho.Service
+	SearchIndexingService() search_indexing.Service
+	AssetUploadService() asset_upload.Service
+	IngestionAuditService() ingestion_audit.Service
+	ContentReadService() content_read.Service
+	SheetSnapshotService() sheet_snapshot.Service
+	NotifierService() notifier.Service
+	ChangeEventService() change_events.Service
+	CdnInvalidationService() cdn_invalidation.Service
 }
@@ -0,0 +1,57 @@
+package ingestion_audit
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/ingestion_audit"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type IngestionAuditService struct {
+	logger     *zap.Logger
+	repository ingestion_audit.MongoRepository
+}
+
+func InitIngestionAuditService(ctx context.Context, repository ingestion_audit.MongoRepository) *IngestionAuditService {
+	return &IngestionAuditService{
+		logger:     logging.WithContext(ctx),
+		repository: repository,
+	}
+}
+
+func (s *IngestionAuditService) Record(ctx context.Context, audit entity.IngestionAudit) error {
+	if audit.Id == "" {
+		audit.Id = uuid.NewString()
+	}
+	if audit.Changes == nil {
+		audit.Changes = []entity.DocumentChange{}
+	}
+	return s.repository.Insert(ctx, audit)
+}
+
+func (s *IngestionAuditService) List(ctx context.Context, query entity.IngestionAuditQuery) (entity.IngestionAuditPage, error) {
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = defaultPageSize
+	}
+	query.PageSize = min(query.PageSize, maxPageSize)
+	audits, total, err := s.repository.Find(ctx, query)
+	if err != nil {
+		return entity.IngestionAuditPage{}, err
+	}
+	return entity.IngestionAuditPage{
+		Items:    audits,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
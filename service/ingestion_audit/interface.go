@@ -0,0 +1,11 @@
+package ingestion_audit
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type Service interface {
+	Record(ctx context.Context, audit entity.IngestionAudit) error
+	List(ctx context.Context, query entity.IngestionAuditQuery) (entity.IngestionAuditPage, error)
+}
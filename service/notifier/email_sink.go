@@ -0,0 +1,90 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+const defaultSmtpPort = 587
+
+// emailSink mails a text summary through an SMTP server, upgrading to TLS when the server
+// offers STARTTLS and logging in when a username is configured.
+type emailSink struct {
+	config  configuration.NotifierSinkConfig
+	timeout time.Duration
+}
+
+func (e *emailSink) Send(ctx context.Context, notification entity.Notification) error {
+	port := e.config.SmtpPort
+	if port == 0 {
+		port = defaultSmtpPort
+	}
+	address := net.JoinHostPort(e.config.SmtpHost, strconv.Itoa(port))
+	dialer := net.Dialer{Timeout: e.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("error connecting to %s: %w", address, err)
+	}
+	if e.timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(e.timeout))
+	}
+	client, err := smtp.NewClient(conn, e.config.SmtpHost)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error starting smtp session with %s: %w", address, err)
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: e.config.SmtpHost}); err != nil {
+			return fmt.Errorf("error starting tls: %w", err)
+		}
+	}
+	if e.config.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", e.config.Username, e.config.Password, e.config.SmtpHost)); err != nil {
+			return fmt.Errorf("error authenticating: %w", err)
+		}
+	}
+	if err = client.Mail(e.config.From); err != nil {
+		return fmt.Errorf("error setting sender: %w", err)
+	}
+	for _, to := range e.config.To {
+		if err = client.Rcpt(to); err != nil {
+			return fmt.Errorf("error adding recipient %s: %w", to, err)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("error starting message: %w", err)
+	}
+	if _, err = writer.Write(e.message(notification)); err != nil {
+		return fmt.Errorf("error writing message: %w", err)
+	}
+	if err = writer.Close(); err != nil {
+		return fmt.Errorf("error sending message: %w", err)
+	}
+	return client.Quit()
+}
+
+func (e *emailSink) message(notification entity.Notification) []byte {
+	body := summary(notification)
+	subject, _, _ := strings.Cut(body, "\n")
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.config.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(e.config.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,127 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+const (
+	signatureHeader = "X-Signature-256"
+	timestampHeader = "X-Signature-Timestamp"
+	// maxListedWarnings is how many warnings a text notification lists
+	maxListedWarnings = 10
+)
+
+// logSink writes notifications to the service log.
+type logSink struct{}
+
+func (logSink) Send(ctx context.Context, notification entity.Notification) error {
+	log.Printf("Notification: %s\n", summary(notification))
+	return nil
+}
+
+// webhookSink posts the notification as JSON. With a secret, X-Signature-256 carries
+// "sha256=" and the hex HMAC-SHA256 of the X-Signature-Timestamp value, a "." and the body,
+// so receivers can reject forged and replayed calls.
+type webhookSink struct {
+	url        string
+	secret     string
+	httpClient *http.Client
+}
+
+func (w *webhookSink) Send(ctx context.Context, notification entity.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("error encoding notification: %w", err)
+	}
+	headers := map[string]string{}
+	if w.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		headers[timestampHeader] = timestamp
+		headers[signatureHeader] = "sha256=" + sign(w.secret, timestamp, body)
+	}
+	return postJSON(ctx, w.httpClient, w.url, body, headers)
+}
+
+func sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// slackSink posts a text summary in the Slack incoming webhook format.
+type slackSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+func (s *slackSink) Send(ctx context.Context, notification entity.Notification) error {
+	body, err := json.Marshal(map[string]string{"text": summary(notification)})
+	if err != nil {
+		return fmt.Errorf("error encoding slack message: %w", err)
+	}
+	return postJSON(ctx, s.httpClient, s.url, body, nil)
+}
+
+func postJSON(ctx context.Context, httpClient *http.Client, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// summary renders the notification as text for chat and email.
+func summary(notification entity.Notification) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", notification.Event, notification.Job)
+	if len(notification.ContentTypes) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(notification.ContentTypes, ", "))
+	}
+	fmt.Fprintf(&b, "\n%s", notification.Message)
+	if notification.RunId != "" {
+		fmt.Fprintf(&b, "\nrun %s", notification.RunId)
+		if notification.Actor != "" {
+			fmt.Fprintf(&b, " by %s", notification.Actor)
+		}
+	}
+	if counts := notification.Counts; counts != nil {
+		fmt.Fprintf(&b, "\n%d inserted, %d updated, %d unchanged, %d skipped, %d warnings",
+			counts.Inserted, counts.Updated, counts.Unchanged, counts.Skipped, counts.Warnings)
+	}
+	for i, warning := range notification.Warnings {
+		if i == maxListedWarnings {
+			fmt.Fprintf(&b, "\n... and %d more", len(notification.Warnings)-maxListedWarnings)
+			break
+		}
+		fmt.Fprintf(&b, "\n- %s", warning)
+	}
+	return b.String()
+}
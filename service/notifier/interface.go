@@ -0,0 +1,17 @@
+package notifier
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type Service interface {
+	Notify(ctx context.Context, notification entity.Notification) error
+	NotifyIngestion(ctx context.Context, audit entity.IngestionAudit)
+	SendTest(ctx context.Context) []entity.NotificationDelivery
+}
+
+// Sink delivers notifications to one destination.
+type Sink interface {
+	Send(ctx context.Context, notification entity.Notification) error
+}
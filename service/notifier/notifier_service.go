@@ -0,0 +1,221 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"go.uber.org/zap"
+)
+
+// defaultRule applies to content types without a rule of their own
+const defaultRule = "default"
+
+// NotifierService sends notifications to the sinks the rule of each content type names.
+type NotifierService struct {
+	logger    *zap.Logger
+	rules     map[string]configuration.NotificationRuleConfig
+	sinkNames []string
+	sinks     map[string]Sink
+}
+
+func InitNotifierService(ctx context.Context,
+	configuration *configuration.Configuration,
+	httpClient *http.Client,
+) (*NotifierService, error) {
+	config := configuration.NotifierConfig
+	s := &NotifierService{
+		logger: logging.WithContext(ctx),
+		rules:  config.Rules,
+		sinks:  make(map[string]Sink),
+	}
+	for _, sinkConfig := range config.Sinks {
+		if _, ok := s.sinks[sinkConfig.Name]; ok {
+			return nil, fmt.Errorf("duplicate notification sink %s", sinkConfig.Name)
+		}
+		sink, err := newSink(sinkConfig, httpClient, config.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		s.sinks[sinkConfig.Name] = sink
+		s.sinkNames = append(s.sinkNames, sinkConfig.Name)
+	}
+	for contentType, rule := range config.Rules {
+		for _, name := range rule.Sinks {
+			if _, ok := s.sinks[name]; !ok {
+				return nil, fmt.Errorf("notification rule %s names unknown sink %s", contentType, name)
+			}
+		}
+	}
+	return s, nil
+}
+
+func newSink(config configuration.NotifierSinkConfig, httpClient *http.Client, timeout time.Duration) (Sink, error) {
+	switch config.Type {
+	case "log":
+		return logSink{}, nil
+	case "webhook":
+		if config.Url == "" {
+			return nil, fmt.Errorf("webhook sink %s has no url", config.Name)
+		}
+		return &webhookSink{url: config.Url, secret: config.Secret, httpClient: httpClient}, nil
+	case "slack":
+		if config.Url == "" {
+			return nil, fmt.Errorf("slack sink %s has no url", config.Name)
+		}
+		return &slackSink{url: config.Url, httpClient: httpClient}, nil
+	case "email":
+		if config.SmtpHost == "" || config.From == "" || len(config.To) == 0 {
+			return nil, fmt.Errorf("email sink %s needs an smtp host, a sender and recipients", config.Name)
+		}
+		return &emailSink{config: config, timeout: timeout}, nil
+	}
+	return nil, fmt.Errorf("notification sink %s has unknown type %q", config.Name, config.Type)
+}
+
+// Notify sends the notification to every sink its content types route it to.
+func (s *NotifierService) Notify(ctx context.Context, notification entity.Notification) error {
+	var errs []error
+	for _, name := range s.route(notification) {
+		if err := s.sinks[name].Send(ctx, notification); err != nil {
+			errs = append(errs, fmt.Errorf("error sending %s notification to %s: %w", notification.Event, name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotifyIngestion sends the outcome of an ingestion run and, per content type, its validation
+// warnings once they reach the rule's threshold. Delivery errors are only logged.
+func (s *NotifierService) NotifyIngestion(ctx context.Context, audit entity.IngestionAudit) {
+	counts := audit.Counts
+	outcome := entity.Notification{
+		Event:        entity.NotificationJobSucceeded,
+		Job:          audit.Endpoint,
+		ContentTypes: audit.ContentTypes,
+		RunId:        audit.Id,
+		Actor:        audit.Actor.Subject,
+		Message:      "ingestion succeeded",
+		Counts:       &counts,
+		OccurredAt:   audit.FinishedAt,
+	}
+	if audit.Outcome == entity.AuditOutcomeFailure {
+		outcome.Event = entity.NotificationJobFailed
+		outcome.Message = audit.Error
+		if outcome.Message == "" {
+			outcome.Message = fmt.Sprintf("ingestion failed with status %d", audit.Status)
+		}
+	}
+	s.deliver(ctx, outcome)
+
+	contentTypes := make([]string, 0, len(audit.WarningCounts))
+	for contentType := range audit.WarningCounts {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+	for _, contentType := range contentTypes {
+		count := audit.WarningCounts[contentType]
+		rule, ok := s.rule(contentType)
+		if !ok || rule.WarningThreshold <= 0 || count < rule.WarningThreshold {
+			continue
+		}
+		var warnings []string
+		for _, warning := range audit.Warnings {
+			if warning.ContentType == contentType {
+				warnings = append(warnings, warning.Message)
+			}
+		}
+		s.deliver(ctx, entity.Notification{
+			Event:        entity.NotificationValidationWarnings,
+			Job:          audit.Endpoint,
+			ContentTypes: []string{contentType},
+			RunId:        audit.Id,
+			Actor:        audit.Actor.Subject,
+			Message:      fmt.Sprintf("%d validation warnings for %s", count, contentType),
+			Counts:       &counts,
+			Warnings:     warnings,
+			OccurredAt:   audit.FinishedAt,
+		})
+	}
+}
+
+// SendTest sends a test notification to every sink, whatever the rules, and reports how each
+// delivery went.
+func (s *NotifierService) SendTest(ctx context.Context) []entity.NotificationDelivery {
+	notification := entity.Notification{
+		Event:      entity.NotificationTest,
+		Job:        "notifier test",
+		Message:    "test notification from the prarthana ingestion service",
+		OccurredAt: time.Now().UTC(),
+	}
+	deliveries := []entity.NotificationDelivery{}
+	for _, name := range s.sinkNames {
+		delivery := entity.NotificationDelivery{Sink: name}
+		if err := s.sinks[name].Send(ctx, notification); err != nil {
+			delivery.Error = err.Error()
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+func (s *NotifierService) deliver(ctx context.Context, notification entity.Notification) {
+	if err := s.Notify(ctx, notification); err != nil {
+		log.Printf("Error delivering notification for %s: %v\n", notification.Job, err)
+	}
+}
+
+// route returns the sinks, in configuration order, of the rules that send the event for the
+// notification's content types.
+func (s *NotifierService) route(notification entity.Notification) []string {
+	contentTypes := notification.ContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = []string{defaultRule}
+	}
+	selected := make(map[string]bool)
+	for _, contentType := range contentTypes {
+		rule, ok := s.rule(contentType)
+		if !ok || !sendsEvent(rule, notification.Event) {
+			continue
+		}
+		for _, name := range s.sinkNames {
+			if len(rule.Sinks) == 0 || slices.Contains(rule.Sinks, name) {
+				selected[name] = true
+			}
+		}
+	}
+	var names []string
+	for _, name := range s.sinkNames {
+		if selected[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (s *NotifierService) rule(contentType string) (configuration.NotificationRuleConfig, bool) {
+	if rule, ok := s.rules[contentType]; ok {
+		return rule, true
+	}
+	rule, ok := s.rules[defaultRule]
+	return rule, ok
+}
+
+func sendsEvent(rule configuration.NotificationRuleConfig, event string) bool {
+	switch event {
+	case entity.NotificationJobSucceeded:
+		return rule.OnSuccess
+	case entity.NotificationJobFailed:
+		return rule.OnFailure
+	case entity.NotificationValidationWarnings:
+		return rule.WarningThreshold > 0
+	}
+	return false
+}
@@ -3,5 +3,5 @@ package prarthana_ingestion
 import "context"
 
 type Service interface {
-	PrarthanaIngestion(ctx context.Context, startID, endID int) (map[string]string, error)
+	PrarthanaIngestion(ctx context.Context, startID, endID int, incremental bool) (map[string]string, error)
 }
@@ -0,0 +1,108 @@
+package prarthana_ingestion
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+)
+
+// prarthanaLanguages maps the language suffix used in prarthana sheet column names to the
+// key stored in the multilingual maps.
+var prarthanaLanguages = []struct {
+	key    string
+	column string
+}{
+	{"default", "Default"},
+	{"hi", "Hindi"},
+	{"kn", "Kannada"},
+	{"mr", "Marathi"},
+	{"ta", "Tamil"},
+	{"te", "Telugu"},
+	{"gu", "Gujarati"},
+}
+
+var listItemPrefix = regexp.MustCompile(`^\s*(\d+[.)]|[-*•])\s*`)
+
+// getLanguageValues reads "<prefix> (<Language>)" columns, skipping blank cells.
+func getLanguageValues(record map[string]interface{}, prefix string) map[string]string {
+	values := make(map[string]string)
+	for _, lang := range prarthanaLanguages {
+		value, ok := record[fmt.Sprintf("%s (%s)", prefix, lang.column)].(string)
+		if ok && strings.TrimSpace(value) != "" {
+			values[lang.key] = strings.TrimSpace(value)
+		}
+	}
+	return values
+}
+
+// getLanguageLists reads "<prefix> (<Language>)" columns holding one item per line,
+// dropping list numbering or bullets editors add in the sheet.
+func getLanguageLists(record map[string]interface{}, prefix string) map[string][]string {
+	lists := make(map[string][]string)
+	for lang, value := range getLanguageValues(record, prefix) {
+		var items []string
+		for _, line := range strings.Split(value, "\n") {
+			item := strings.TrimSpace(listItemPrefix.ReplaceAllString(line, ""))
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) > 0 {
+			lists[lang] = items
+		}
+	}
+	return lists
+}
+
+// validateLanguageCoverage requires a default value whenever any translation is given and
+// records a warning for each language still missing one.
+func validateLanguageCoverage(ctx context.Context, id int, field string, present map[string]bool) error {
+	if len(present) == 0 {
+		return nil
+	}
+	if !present["default"] {
+		return fmt.Errorf("%s has translations but no default value : %d", field, id)
+	}
+	for _, lang := range prarthanaLanguages {
+		if !present[lang.key] {
+			util.RecordWarning(ctx, entity.ContentPrarthanas, "Missing %s for language '%s' in prarthana %d", field, lang.key, id)
+		}
+	}
+	return nil
+}
+
+func getImportance(ctx context.Context, record map[string]interface{}, id int) (map[string]string, error) {
+	importance := getLanguageValues(record, "Importance")
+	return importance, validateLanguageCoverage(ctx, id, "importance", keysOf(importance))
+}
+
+func getInstruction(ctx context.Context, record map[string]interface{}, id int) (map[string]string, error) {
+	instruction := getLanguageValues(record, "Instruction")
+	return instruction, validateLanguageCoverage(ctx, id, "instruction", keysOf(instruction))
+}
+
+// getItemsRequired also checks every translated list has as many items as the default one,
+// since the app renders them side by side by position.
+func getItemsRequired(ctx context.Context, record map[string]interface{}, id int) (map[string][]string, error) {
+	itemsRequired := getLanguageLists(record, "Items Required")
+	present := make(map[string]bool)
+	for lang, items := range itemsRequired {
+		present[lang] = true
+		if defaultItems, ok := itemsRequired["default"]; ok && len(items) != len(defaultItems) {
+			return nil, fmt.Errorf("items required for language '%s' has %d items but default has %d : %d", lang, len(items), len(defaultItems), id)
+		}
+	}
+	return itemsRequired, validateLanguageCoverage(ctx, id, "items required", present)
+}
+
+func keysOf(values map[string]string) map[string]bool {
+	keys := make(map[string]bool)
+	for key := range values {
+		keys[key] = true
+	}
+	return keys
+}
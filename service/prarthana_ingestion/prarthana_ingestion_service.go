@@ -6,38 +6,77 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
 	mongoRepo "github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/prarthana_data"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_url"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_verifier"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/audio_stitching"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/deity_prarthana_link"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/search_indexing"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/transcoding"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/zoho"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"io"
 	"log"
 	"math"
+	"net/http"
 	"os"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
 )
 
 type PrarthanaIngestionService struct {
-	logger                   *zap.Logger
-	prarthanaMongoRepository mongoRepo.MongoRepository
-	zohoService              zoho.Service
+	logger                    *zap.Logger
+	prarthanaMongoRepository  mongoRepo.MongoRepository
+	zohoService               zoho.Service
+	searchIndexingService     search_indexing.Service
+	deityPrarthanaLinkService deity_prarthana_link.Service
+	assetUrlService           asset_url.Service
+	assetVerifierService      asset_verifier.Service
+	audioStitchingService     audio_stitching.Service
+	transcodingService        transcoding.Service
+	httpClient                *http.Client
+	stitchAudio               bool
 }
 
 func InitPrathanaIngestionService(ctx context.Context,
+	configuration *configuration.Configuration,
 	prarthanaMongoRepository mongoRepo.MongoRepository,
 	zohoService zoho.Service,
+	searchIndexingService search_indexing.Service,
+	deityPrarthanaLinkService deity_prarthana_link.Service,
+	assetUrlService asset_url.Service,
+	assetVerifierService asset_verifier.Service,
+	audioStitchingService audio_stitching.Service,
+	transcodingService transcoding.Service,
+	httpClient *http.Client,
 ) *PrarthanaIngestionService {
 	return &PrarthanaIngestionService{
-		logger:                   logging.WithContext(ctx),
-		prarthanaMongoRepository: prarthanaMongoRepository,
-		zohoService:              zohoService,
+		logger:                    logging.WithContext(ctx),
+		prarthanaMongoRepository:  prarthanaMongoRepository,
+		zohoService:               zohoService,
+		searchIndexingService:     searchIndexingService,
+		deityPrarthanaLinkService: deityPrarthanaLinkService,
+		assetUrlService:           assetUrlService,
+		assetVerifierService:      assetVerifierService,
+		audioStitchingService:     audioStitchingService,
+		transcodingService:        transcodingService,
+		httpClient:                httpClient,
+		stitchAudio:               configuration.StitchingConfig.Enabled,
 	}
 }
 
-func (s *PrarthanaIngestionService) PrarthanaIngestion(ctx context.Context, startID, endID int) (map[string]string, error) {
+// PrarthanaIngestion ingests the prarthanas with IDs in range. The source hash of a prarthana
+// covers its row, its resolved variant and the source hashes of its stotras (so stotra
+// changes carry over), its deities, the validators of its uploaded audio and whether
+// stitching and transcoding are on; incrementally, prarthanas whose hash matches the stored
+// one are skipped.
+func (s *PrarthanaIngestionService) PrarthanaIngestion(ctx context.Context, startID, endID int, incremental bool) (map[string]string, error) {
 	stotraMap, err := s.prarthanaMongoRepository.GetAllStotras(ctx)
 	if err != nil {
 		return nil, err
@@ -52,6 +91,19 @@ func (s *PrarthanaIngestionService) PrarthanaIngestion(ctx context.Context, star
 		log.Fatalf("Failed to prepare chapter map: %v", err)
 	}
 
+	mapping, err := s.deityPrarthanaLinkService.GetMapping(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deities, err := s.prarthanaMongoRepository.GetAllDeities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deityByTmpId := make(map[string]entity.DeityDocument)
+	for _, deity := range deities {
+		deityByTmpId[deity.TmpId] = deity
+	}
+
 	var response entity.ShlokaSheetResponse
 	err = s.zohoService.GetSheetData(ctx, "prarthanas", &response)
 	if err != nil {
@@ -60,8 +112,19 @@ func (s *PrarthanaIngestionService) PrarthanaIngestion(ctx context.Context, star
 	if len(response.Records) == 0 {
 		return nil, errors.New("no records found")
 	}
+	audio, err := s.resolveAudio(ctx, response.Records, startID, endID)
+	if err != nil {
+		return nil, err
+	}
+	var storedHashes map[string]string
+	if incremental {
+		if storedHashes, err = s.prarthanaMongoRepository.GetSourceHashes(ctx, entity.ContentPrarthanas); err != nil {
+			return nil, err
+		}
+	}
 	prarthanaIdMap := make(map[string]string)
 	prarthanas := make([]entity.Prarthana, 0)
+	var assetChecks []entity.AssetCheck
 	for i, record := range response.Records {
 		fmt.Println("Processing record : ", i+1)
 		idf, ok := record["ID"].(float64)
@@ -73,6 +136,15 @@ func (s *PrarthanaIngestionService) PrarthanaIngestion(ctx context.Context, star
 		if id < startID || id > endID {
 			continue
 		}
+		tmpId := strconv.Itoa(id)
+		variantIds := fmt.Sprintf("%v", record["Prarthana Variant ID (Comma separated - Ordered)"])
+		sourceHash := util.SourceHash(record, variantMap[variantIds], mapping.PrarthanaToDeities[tmpId], s.stitchAudio,
+			stotraHashes(variantMap[variantIds], stotraMap), audio[tmpId].ETag, audio[tmpId].LastModified, s.transcodingService.Enabled())
+		if incremental && storedHashes[tmpId] == sourceHash {
+			log.Printf("Skipping unchanged prarthana %d\n", id)
+			util.RecordChange(ctx, entity.ContentPrarthanas, tmpId, entity.ChangeSkipped, nil)
+			continue
+		}
 
 		nameDefault, ok := record["Name (Mandatory) (Default)"].(string)
 		if !ok {
@@ -89,8 +161,6 @@ func (s *PrarthanaIngestionService) PrarthanaIngestion(ctx context.Context, star
 		if re.MatchString(nameDefault) {
 			return nil, fmt.Errorf("the name '%s' contains special characters. Please remove them", nameDefault)
 		}
-		tmpId := strconv.Itoa(id)
-
 		extId, ok := record["UUID"].(string)
 		if !ok {
 			extId = uuid.NewString()
@@ -103,19 +173,10 @@ func (s *PrarthanaIngestionService) PrarthanaIngestion(ctx context.Context, star
 		}
 		audioName := strings.ToLower(util.SanitizeString(nameDefault))
 
-		audioURL := fmt.Sprintf("https://d161fa2zahtt3z.cloudfront.net/audio/stitched_audio/%s.wav", audioName)
-		audioURLMp3 := fmt.Sprintf("https://d161fa2zahtt3z.cloudfront.net/audio/stitched_audio/%s.mp3", audioName)
-		if !util.UrlExists(audioURL) {
-			if !util.UrlExists(audioURLMp3) {
-				return nil, fmt.Errorf("audio URL does not exist: %s", audioURL)
-			}
-			audioURL = audioURLMp3
-		}
-
-		albumArtURL := fmt.Sprintf("https://d161fa2zahtt3z.cloudfront.net/prarthanas/album_art/%s.png", albumArt)
-		if !util.UrlExists(albumArtURL) {
-			return nil, fmt.Errorf("album art URL does not exist: %s", albumArtURL)
-		}
+		// the .wav URL is replaced by the verified or stitched one
+		audioURL := s.assetUrlService.Url(asset_url.StitchedAudio, audioName+".wav")
+		albumArtURL := s.assetUrlService.Url(asset_url.AlbumArt, albumArt)
+		assetChecks = append(assetChecks, entity.AssetCheck{Row: id, Kind: asset_url.AlbumArt, Candidates: []string{albumArtURL}})
 
 		studioRecorded := false
 		studioRecordedStr, ok := record["Studio Recorded(yes/no)"].(string)
@@ -144,8 +205,20 @@ func (s *PrarthanaIngestionService) PrarthanaIngestion(ctx context.Context, star
 		shortDescriptionTelugu, ok := record["Short Description (Telugu)"].(string)
 		shortDescriptionGujarati, ok := record["Short Description (Gujarati)"].(string)
 
+		importance, err := getImportance(ctx, record, id)
+		if err != nil {
+			return nil, err
+		}
+		instruction, err := getInstruction(ctx, record, id)
+		if err != nil {
+			return nil, err
+		}
+		itemsRequired, err := getItemsRequired(ctx, record, id)
+		if err != nil {
+			return nil, err
+		}
+
 		//variantIds, ok := record["Prarthana Variant ID (Comma separated - Ordered)"].(string)
-		variantIds := fmt.Sprintf("%v", record["Prarthana Variant ID (Comma separated - Ordered)"])
 		prarthana := entity.Prarthana{
 			TmpId: tmpId,
 			Id:    extId,
@@ -165,10 +238,11 @@ func (s *PrarthanaIngestionService) PrarthanaIngestion(ctx context.Context, star
 				IsStudioRecorded: studioRecorded},
 			Variants:      []entity.Variant{variantMap[variantIds]},
 			Description:   map[string]string{"default": shortDescriptionDefault, "hi": shortDescriptionHindi, "kn": shortDescriptionKannada, "mr": shortDescriptionMarathi, "ta": shortDescriptionTamil, "te": shortDescriptionTelugu, "gu": shortDescriptionGujarati},
-			Importance:    map[string]string{},
-			Instruction:   map[string]string{},
-			ItemsRequired: map[string][]string{},
+			Importance:    importance,
+			Instruction:   instruction,
+			ItemsRequired: itemsRequired,
 			IntentBased:   intentBasedFlag,
+			SourceHash:    sourceHash,
 		}
 		templateNumberS, ok := record["Template Number Int"].(string)
 		if !ok {
@@ -179,8 +253,8 @@ func (s *PrarthanaIngestionService) PrarthanaIngestion(ctx context.Context, star
 			return nil, err
 		}
 		prarthana.UiInfo = entity.PrarthanaUIInfo{
-			AlbumArt:        fmt.Sprintf("https://d161fa2zahtt3z.cloudfront.net/prarthanas/album_art/%s.png", albumArt),
-			DefaultImageUrl: fmt.Sprintf("https://d161fa2zahtt3z.cloudfront.net/prarthanas/album_art/%s.png", albumArt),
+			AlbumArt:        albumArtURL,
+			DefaultImageUrl: albumArtURL,
 			TemplateNumber:  fmt.Sprintf("template_%v", templateNumber),
 		}
 
@@ -199,10 +273,173 @@ func (s *PrarthanaIngestionService) PrarthanaIngestion(ctx context.Context, star
 			{"assamese", "অসমীয়া"},
 			{"punjabi", "ਪੰਜਾਬੀ"},
 		}
+		keywordBuilder := util.NewKeywordBuilder().
+			AddMap(prarthana.Title).
+			Add(prarthana.FestivalIds...)
+		prarthana.DeityIds = []string{}
+		for _, deityTmpId := range mapping.PrarthanaToDeities[tmpId] {
+			if deity, ok := deityByTmpId[deityTmpId]; ok {
+				prarthana.DeityIds = append(prarthana.DeityIds, deity.Id)
+				keywordBuilder.AddMap(deity.Title)
+			}
+		}
+		prarthana.SearchKeywords = keywordBuilder.Keywords()
 		prarthanas = append(prarthanas, prarthana)
 		prarthanaIdMap[tmpId] = prarthana.Id
 	}
-	return prarthanaIdMap, s.prarthanaMongoRepository.InsertManyPrarthanas(ctx, prarthanas)
+
+	if _, missingErr := s.assetVerifierService.Verify(ctx, "prarthana", assetChecks); missingErr != nil {
+		return nil, missingErr
+	}
+	for i := range prarthanas {
+		if s.stitchAudio {
+			if err := s.stitchPrarthanaAudio(ctx, &prarthanas[i], stotraMap); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		prarthanas[i].AudioInfo.AudioUrl = audio[prarthanas[i].TmpId].Url
+		transcoded := s.transcodeAudio(ctx, prarthanas[i].AudioInfo.AudioUrl)
+		prarthanas[i].AudioInfo.HlsUrl = transcoded.HlsUrl
+		prarthanas[i].AudioInfo.Renditions = transcoded.Renditions
+	}
+
+	if err := s.prarthanaMongoRepository.InsertManyPrarthanas(ctx, prarthanas); err != nil {
+		return nil, err
+	}
+	if err := s.deityPrarthanaLinkService.SyncLinks(ctx, mapping); err != nil {
+		return nil, err
+	}
+	tmpIds := make([]string, 0, len(prarthanaIdMap))
+	for tmpId := range prarthanaIdMap {
+		tmpIds = append(tmpIds, tmpId)
+	}
+	if err := s.searchIndexingService.IndexPrarthanas(ctx, tmpIds); err != nil {
+		log.Printf("Failed to index prarthanas for search: %v\n", err)
+	}
+	return prarthanaIdMap, nil
+}
+
+// resolveAudio verifies the uploaded audio of the prarthanas in range in one batch, returning
+// the result for the .wav (or fallback .mp3) of every row by its ID. Its validators feed the
+// source hash, so they are requested fresh. With stitching on, the audio is generated instead
+// and nothing is verified.
+func (s *PrarthanaIngestionService) resolveAudio(ctx context.Context, records []map[string]interface{}, startID, endID int) (map[string]entity.AssetCheckResult, error) {
+	audio := make(map[string]entity.AssetCheckResult)
+	if s.stitchAudio {
+		return audio, nil
+	}
+	var checks []entity.AssetCheck
+	for _, record := range records {
+		idf, ok := record["ID"].(float64)
+		if !ok {
+			return nil, errors.New("Invalid ID")
+		}
+		id := int(idf)
+		if id < startID || id > endID {
+			continue
+		}
+		nameDefault, ok := record["Name (Mandatory) (Default)"].(string)
+		if !ok {
+			return nil, errors.New("Missing prarthana name")
+		}
+		audioName := strings.ToLower(util.SanitizeString(nameDefault))
+		checks = append(checks, entity.AssetCheck{
+			Row:  id,
+			Kind: asset_url.StitchedAudio,
+			Candidates: []string{
+				s.assetUrlService.Url(asset_url.StitchedAudio, audioName+".wav"),
+				s.assetUrlService.Url(asset_url.StitchedAudio, audioName+".mp3"),
+			},
+		})
+	}
+	results, missingErr := s.assetVerifierService.VerifyFresh(ctx, "prarthana", checks)
+	if missingErr != nil {
+		return nil, missingErr
+	}
+	for _, result := range results {
+		audio[strconv.Itoa(result.Row)] = result
+	}
+	return audio, nil
+}
+
+// stotraHashes are the source hashes of the stotras of the variant, in chapter order, so a
+// changed stotra re-stitches the prarthana.
+func stotraHashes(variant entity.Variant, stotraMap map[string]entity.Stotra) []string {
+	var hashes []string
+	for _, chapter := range variant.Chapters {
+		for _, stotraId := range chapter.StotraIds {
+			hashes = append(hashes, stotraMap[stotraId].SourceHash)
+		}
+	}
+	return hashes
+}
+
+// stitchPrarthanaAudio generates the prarthana audio from its variant's stotras and records
+// where each chapter starts in it.
+func (s *PrarthanaIngestionService) stitchPrarthanaAudio(ctx context.Context, prarthana *entity.Prarthana, stotraMap map[string]entity.Stotra) error {
+	variant := prarthana.Variants[0]
+	name := strings.ToLower(util.SanitizeString(prarthana.Title["default"]))
+	stitched, err := s.audioStitchingService.Stitch(ctx, name, variant, stotraMap)
+	if err != nil {
+		return fmt.Errorf("error stitching audio of prarthana %s: %w", prarthana.TmpId, err)
+	}
+	chapters := make([]entity.Chapter, len(variant.Chapters))
+	copy(chapters, variant.Chapters)
+	for i, offset := range stitched.Chapters {
+		chapters[i].StartMs = offset.StartMs
+		chapters[i].DurationMs = offset.DurationMs
+	}
+	variant.Chapters = chapters
+	prarthana.Variants[0] = variant
+	prarthana.AudioInfo.AudioUrl = stitched.Url
+	prarthana.AudioInfo.WaveformUrl = stitched.WaveformUrl
+	prarthana.AudioInfo.HlsUrl = stitched.Transcoded.HlsUrl
+	prarthana.AudioInfo.Renditions = stitched.Transcoded.Renditions
+	return nil
+}
+
+// transcodeAudio downloads the verified prarthana audio and transcodes it, as stitching does
+// for the audio it generates. Failures are logged and leave the prarthana without renditions.
+func (s *PrarthanaIngestionService) transcodeAudio(ctx context.Context, audioUrl string) entity.TranscodedAudio {
+	if !s.transcodingService.Enabled() || audioUrl == "" {
+		return entity.TranscodedAudio{}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, audioUrl, nil)
+	if err != nil {
+		log.Printf("Error accessing prarthana audio: %s, Error: %v\n", audioUrl, err)
+		return entity.TranscodedAudio{}
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		if err == nil {
+			resp.Body.Close()
+		}
+		log.Printf("Error accessing prarthana audio: %s, Error: %v\n", audioUrl, err)
+		return entity.TranscodedAudio{}
+	}
+	defer resp.Body.Close()
+
+	name := path.Base(audioUrl)
+	ext := path.Ext(name)
+	tempFile, err := os.CreateTemp("", "*"+ext)
+	if err != nil {
+		log.Println("Error creating temp file:", err)
+		return entity.TranscodedAudio{}
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+	if _, err = io.Copy(tempFile, resp.Body); err != nil {
+		log.Println("Error saving audio file:", err)
+		return entity.TranscodedAudio{}
+	}
+
+	transcoded, err := s.transcodingService.Transcode(ctx, "prarthanas/"+name[:len(name)-len(ext)], tempFile.Name())
+	if err != nil {
+		log.Printf("Error transcoding %s: %v\n", audioUrl, err)
+		return entity.TranscodedAudio{}
+	}
+	return transcoded
 }
 
 func (s *PrarthanaIngestionService) prepareChapterMap(ctx context.Context, stotraMap map[string]entity.Stotra) (map[string]entity.Chapter, error) {
@@ -0,0 +1,9 @@
+package scheduler
+
+import (
+	"context"
+)
+
+type Service interface {
+	Start(ctx context.Context) error
+}
@@ -0,0 +1,254 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/job_lease"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/cdn_invalidation"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/deity_ingestion"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/ingestion_audit"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/notifier"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/prarthana_ingestion"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/shlok_ingestion"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/stotra_ingestion"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// schedulerSubject is the caller scheduled runs are recorded under in the audit log
+const schedulerSubject = "scheduler"
+
+var errLeaseLost = errors.New("job lease lost to another instance")
+
+var schedulableContentTypes = []string{entity.ContentShloks, entity.ContentStotras, entity.ContentPrarthanas, entity.ContentDeities}
+
+// SchedulerService runs the configured incremental ingestions on their cron schedules. Every
+// instance schedules every job, and a Mongo lease per job makes sure only one of them runs it.
+type SchedulerService struct {
+	logger                    *zap.Logger
+	config                    configuration.SchedulerConfig
+	holder                    string
+	leaseRepository           job_lease.MongoRepository
+	shlokIngestionService     shlok_ingestion.Service
+	stotraIngestionService    stotra_ingestion.Service
+	prarthanaIngestionService prarthana_ingestion.Service
+	deityIngestionService     deity_ingestion.Service
+	ingestionAuditService     ingestion_audit.Service
+	notifierService           notifier.Service
+	cdnInvalidationService    cdn_invalidation.Service
+}
+
+func InitSchedulerService(ctx context.Context,
+	configuration *configuration.Configuration,
+	leaseRepository job_lease.MongoRepository,
+	shlokIngestionService shlok_ingestion.Service,
+	stotraIngestionService stotra_ingestion.Service,
+	prarthanaIngestionService prarthana_ingestion.Service,
+	deityIngestionService deity_ingestion.Service,
+	ingestionAuditService ingestion_audit.Service,
+	notifierService notifier.Service,
+	cdnInvalidationService cdn_invalidation.Service,
+) *SchedulerService {
+	hostname, _ := os.Hostname()
+	return &SchedulerService{
+		logger:                    logging.WithContext(ctx),
+		config:                    configuration.SchedulerConfig,
+		holder:                    hostname + "-" + uuid.NewString(),
+		leaseRepository:           leaseRepository,
+		shlokIngestionService:     shlokIngestionService,
+		stotraIngestionService:    stotraIngestionService,
+		prarthanaIngestionService: prarthanaIngestionService,
+		deityIngestionService:     deityIngestionService,
+		ingestionAuditService:     ingestionAuditService,
+		notifierService:           notifierService,
+		cdnInvalidationService:    cdnInvalidationService,
+	}
+}
+
+// Start validates the configured jobs and schedules them. Jobs run in the background until
+// the process exits; a run still going when its next time comes skips that time.
+func (s *SchedulerService) Start(ctx context.Context) error {
+	location := time.UTC
+	if s.config.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(s.config.Timezone); err != nil {
+			return fmt.Errorf("invalid scheduler timezone %s: %w", s.config.Timezone, err)
+		}
+	}
+	if s.config.LeaseTTL <= 0 {
+		return errors.New("scheduler lease TTL must be positive")
+	}
+	scheduler := cron.New(cron.WithLocation(location), cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	names := make(map[string]bool)
+	for _, job := range s.config.Jobs {
+		if err := validateJob(job); err != nil {
+			return err
+		}
+		if names[job.Name] {
+			return fmt.Errorf("duplicate scheduled job %s", job.Name)
+		}
+		names[job.Name] = true
+		if _, err := scheduler.AddFunc(job.Cron, func() { s.runJob(ctx, job) }); err != nil {
+			return fmt.Errorf("invalid cron expression %q of job %s: %w", job.Cron, job.Name, err)
+		}
+	}
+	scheduler.Start()
+	log.Printf("Scheduled %d ingestion job(s) on %s\n", len(s.config.Jobs), s.holder)
+	return nil
+}
+
+// jobEndpoint stands in for the endpoint of scheduled runs in audit records and notifications.
+func jobEndpoint(job configuration.ScheduledJobConfig) string {
+	return "SCHEDULE " + job.Name
+}
+
+func validateJob(job configuration.ScheduledJobConfig) error {
+	if job.Name == "" {
+		return errors.New("scheduled job without a name")
+	}
+	if len(job.ContentTypes) == 0 {
+		return fmt.Errorf("scheduled job %s has no content types", job.Name)
+	}
+	for _, contentType := range job.ContentTypes {
+		if !slices.Contains(schedulableContentTypes, contentType) {
+			return fmt.Errorf("scheduled job %s has unknown content type %s", job.Name, contentType)
+		}
+	}
+	if job.StartID < 1 || job.EndID < job.StartID {
+		return fmt.Errorf("scheduled job %s has invalid ID range %d - %d", job.Name, job.StartID, job.EndID)
+	}
+	return nil
+}
+
+// runJob runs the job if this instance gets its lease. The run is recorded in the ingestion
+// audit log like an API request and its outcome is sent to the notifier.
+func (s *SchedulerService) runJob(ctx context.Context, job configuration.ScheduledJobConfig) {
+	leaseName := "scheduler:" + job.Name
+	acquired, err := s.leaseRepository.Acquire(ctx, leaseName, s.holder, s.config.LeaseTTL)
+	if err != nil {
+		log.Printf("Error acquiring lease of job %s: %v\n", job.Name, err)
+		s.notifyFailure(ctx, job, fmt.Sprintf("job did not start: %v", err))
+		return
+	}
+	if !acquired {
+		log.Printf("Skipping job %s, another instance holds its lease\n", job.Name)
+		return
+	}
+	defer func() {
+		if err := s.leaseRepository.Release(context.WithoutCancel(ctx), leaseName, s.holder); err != nil {
+			log.Printf("Error releasing lease of job %s: %v\n", job.Name, err)
+		}
+	}()
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go s.renewLease(runCtx, cancel, leaseName)
+
+	principal := &entity.Principal{Subject: schedulerSubject, Role: entity.RolePublisher, Method: "schedule"}
+	audit := entity.IngestionAudit{
+		Id:           uuid.NewString(),
+		Actor:        *principal,
+		Endpoint:     jobEndpoint(job),
+		StartID:      job.StartID,
+		EndID:        job.EndID,
+		ContentTypes: job.ContentTypes,
+		StartedAt:    time.Now().UTC(),
+	}
+	trail := util.NewAuditTrail()
+	runCtx = util.SetAuditTrailInContext(util.SetPrincipalInContext(runCtx, principal), trail)
+	runCtx = util.SetRunIdInContext(runCtx, audit.Id)
+	log.Printf("Running scheduled job %s (run %s)\n", job.Name, audit.Id)
+	err = s.ingest(runCtx, job)
+	if err != nil && errors.Is(context.Cause(runCtx), errLeaseLost) {
+		err = fmt.Errorf("%w: %v", errLeaseLost, err)
+	}
+	// assets overwritten before a failure are invalidated too
+	if invalidateErr := s.cdnInvalidationService.InvalidateChangedAssets(context.WithoutCancel(runCtx)); invalidateErr != nil {
+		log.Printf("Error invalidating assets changed by job %s: %v\n", job.Name, invalidateErr)
+	}
+
+	audit.FinishedAt = time.Now().UTC()
+	audit.Outcome = entity.AuditOutcomeSuccess
+	if err != nil {
+		audit.Outcome = entity.AuditOutcomeFailure
+		audit.Error = err.Error()
+	}
+	trail.Fill(&audit)
+	if recordErr := s.ingestionAuditService.Record(context.WithoutCancel(ctx), audit); recordErr != nil {
+		log.Printf("Error recording ingestion audit for job %s: %v\n", job.Name, recordErr)
+	}
+	s.notifierService.NotifyIngestion(context.WithoutCancel(ctx), audit)
+	if err != nil {
+		log.Printf("Scheduled job %s failed: %v\n", job.Name, err)
+		return
+	}
+	log.Printf("Scheduled job %s finished: %d inserted, %d updated, %d skipped\n", job.Name, audit.Counts.Inserted, audit.Counts.Updated, audit.Counts.Skipped)
+}
+
+// ingest runs the job's content types in order, stopping at the first failure since later
+// types reference the earlier ones.
+func (s *SchedulerService) ingest(ctx context.Context, job configuration.ScheduledJobConfig) error {
+	for _, contentType := range job.ContentTypes {
+		var err error
+		switch contentType {
+		case entity.ContentShloks:
+			err = s.shlokIngestionService.ShlokIngestion(ctx, job.StartID, job.EndID, true)
+		case entity.ContentStotras:
+			_, err = s.stotraIngestionService.StotraIngestion(ctx, job.StartID, job.EndID, true)
+		case entity.ContentPrarthanas:
+			_, err = s.prarthanaIngestionService.PrarthanaIngestion(ctx, job.StartID, job.EndID, true)
+		case entity.ContentDeities:
+			_, err = s.deityIngestionService.DeityIngestion(ctx, job.StartID, job.EndID, true)
+		}
+		if err != nil {
+			return fmt.Errorf("error ingesting %s: %w", contentType, err)
+		}
+	}
+	return nil
+}
+
+// renewLease keeps the lease alive while the run lasts and cancels the run if it is lost.
+// Failed renewals are retried, the lease stays valid until it expires.
+func (s *SchedulerService) renewLease(ctx context.Context, cancel context.CancelCauseFunc, leaseName string) {
+	ticker := time.NewTicker(s.config.LeaseTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			renewed, err := s.leaseRepository.Renew(ctx, leaseName, s.holder, s.config.LeaseTTL)
+			if err != nil {
+				log.Printf("Error renewing lease %s: %v\n", leaseName, err)
+				continue
+			}
+			if !renewed {
+				cancel(errLeaseLost)
+				return
+			}
+		}
+	}
+}
+
+func (s *SchedulerService) notifyFailure(ctx context.Context, job configuration.ScheduledJobConfig, message string) {
+	err := s.notifierService.Notify(context.WithoutCancel(ctx), entity.Notification{
+		Event:        entity.NotificationJobFailed,
+		Job:          jobEndpoint(job),
+		ContentTypes: job.ContentTypes,
+		Message:      message,
+		OccurredAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("Error sending notification for job %s: %v\n", job.Name, err)
+	}
+}
@@ -0,0 +1,13 @@
+package search_indexing
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type Service interface {
+	IndexPrarthanas(ctx context.Context, tmpIds []string) error
+	IndexDeities(ctx context.Context, tmpIds []string) error
+	IndexStotras(ctx context.Context, stotras []entity.Stotra) error
+	Reindex(ctx context.Context) error
+}
@@ -0,0 +1,86 @@
+package search_indexing
+
+import (
+	"context"
+	"errors"
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	esRepo "github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/es/prarthana"
+	mongoRepo "github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/prarthana_data"
+	"go.uber.org/zap"
+)
+
+type SearchIndexingService struct {
+	logger                   *zap.Logger
+	enabled                  bool
+	prarthanaMongoRepository mongoRepo.MongoRepository
+	elasticRepository        esRepo.ElasticRepository
+}
+
+func InitSearchIndexingService(ctx context.Context,
+	configuration *configuration.Configuration,
+	prarthanaMongoRepository mongoRepo.MongoRepository,
+	elasticRepository esRepo.ElasticRepository,
+) *SearchIndexingService {
+	return &SearchIndexingService{
+		logger:                   logging.WithContext(ctx),
+		enabled:                  configuration.ElasticConfig.Enabled,
+		prarthanaMongoRepository: prarthanaMongoRepository,
+		elasticRepository:        elasticRepository,
+	}
+}
+
+// IndexPrarthanas re-reads the given prarthanas from Mongo so the indexed ids are
+// the ones the repository actually stored.
+func (s *SearchIndexingService) IndexPrarthanas(ctx context.Context, tmpIds []string) error {
+	if !s.enabled || len(tmpIds) == 0 {
+		return nil
+	}
+	prarthanas, err := s.prarthanaMongoRepository.GetPrarthanasByTmpIds(ctx, tmpIds)
+	if err != nil {
+		return err
+	}
+	return s.elasticRepository.IndexPrarthanas(ctx, prarthanas)
+}
+
+func (s *SearchIndexingService) IndexDeities(ctx context.Context, tmpIds []string) error {
+	if !s.enabled || len(tmpIds) == 0 {
+		return nil
+	}
+	deities, err := s.prarthanaMongoRepository.GetDeitiesByTmpIds(ctx, tmpIds)
+	if err != nil {
+		return err
+	}
+	return s.elasticRepository.IndexDeities(ctx, deities)
+}
+
+func (s *SearchIndexingService) IndexStotras(ctx context.Context, stotras []entity.Stotra) error {
+	if !s.enabled {
+		return nil
+	}
+	return s.elasticRepository.IndexStotras(ctx, stotras)
+}
+
+func (s *SearchIndexingService) Reindex(ctx context.Context) error {
+	if !s.enabled {
+		return errors.New("search indexing is disabled")
+	}
+	prarthanas, err := s.prarthanaMongoRepository.GetAllPrarthanas(ctx)
+	if err != nil {
+		return err
+	}
+	deities, err := s.prarthanaMongoRepository.GetAllDeities(ctx)
+	if err != nil {
+		return err
+	}
+	stotraMap, err := s.prarthanaMongoRepository.GetAllStotras(ctx)
+	if err != nil {
+		return err
+	}
+	stotras := make([]entity.Stotra, 0, len(stotraMap))
+	for _, stotra := range stotraMap {
+		stotras = append(stotras, stotra)
+	}
+	return s.elasticRepository.Reindex(ctx, prarthanas, deities, stotras)
+}
@@ -0,0 +1,15 @@
+package sheet_snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type Service interface {
+	Enabled() bool
+	Save(ctx context.Context, sheetId string, worksheet string, records json.RawMessage) (entity.SheetSnapshot, error)
+	Load(ctx context.Context, id string) (entity.SheetSnapshot, json.RawMessage, error)
+	List(ctx context.Context, query entity.SheetSnapshotQuery) (entity.SheetSnapshotPage, error)
+	Diff(ctx context.Context, fromId string, toId string) (entity.SnapshotDiff, error)
+}
@@ -0,0 +1,132 @@
+package sheet_snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/sheet_snapshot"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type SheetSnapshotService struct {
+	logger     *zap.Logger
+	config     configuration.SnapshotConfig
+	repository sheet_snapshot.MongoRepository
+}
+
+func InitSheetSnapshotService(ctx context.Context,
+	configuration *configuration.Configuration,
+	repository sheet_snapshot.MongoRepository,
+) *SheetSnapshotService {
+	return &SheetSnapshotService{
+		logger:     logging.WithContext(ctx),
+		config:     configuration.SnapshotConfig,
+		repository: repository,
+	}
+}
+
+// Enabled reports whether live fetches are snapshotted; stored snapshots can always be loaded.
+func (s *SheetSnapshotService) Enabled() bool {
+	return s.config.Enabled
+}
+
+// Save stores the fetched records of a worksheet. When they are identical to the latest
+// snapshot of the worksheet, that snapshot is returned instead of storing a copy.
+func (s *SheetSnapshotService) Save(ctx context.Context, sheetId string, worksheet string, records json.RawMessage) (entity.SheetSnapshot, error) {
+	sum := sha256.Sum256(records)
+	checksum := hex.EncodeToString(sum[:])
+	latest, err := s.repository.GetLatest(ctx, sheetId, worksheet)
+	if err == nil && latest.Checksum == checksum {
+		return latest, nil
+	}
+	if err != nil && !errors.Is(err, entity.ErrSnapshotNotFound) {
+		return entity.SheetSnapshot{}, err
+	}
+
+	var rows []json.RawMessage
+	if err := json.Unmarshal(records, &rows); err != nil {
+		return entity.SheetSnapshot{}, fmt.Errorf("error reading records of %s: %w", worksheet, err)
+	}
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(records); err != nil {
+		return entity.SheetSnapshot{}, fmt.Errorf("error compressing snapshot of %s: %w", worksheet, err)
+	}
+	if err := writer.Close(); err != nil {
+		return entity.SheetSnapshot{}, fmt.Errorf("error compressing snapshot of %s: %w", worksheet, err)
+	}
+	snapshot := entity.SheetSnapshot{
+		Id:          uuid.NewString(),
+		SheetId:     sheetId,
+		Worksheet:   worksheet,
+		Checksum:    checksum,
+		FetchedAt:   time.Now().UTC(),
+		RecordCount: len(rows),
+		Size:        compressed.Len(),
+		Data:        compressed.Bytes(),
+	}
+	if err := s.repository.Insert(ctx, snapshot); err != nil {
+		return entity.SheetSnapshot{}, err
+	}
+	snapshot.Data = nil
+	return snapshot, nil
+}
+
+// Load returns a snapshot and its records, verified against the stored checksum.
+func (s *SheetSnapshotService) Load(ctx context.Context, id string) (entity.SheetSnapshot, json.RawMessage, error) {
+	snapshot, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return entity.SheetSnapshot{}, nil, err
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(snapshot.Data))
+	if err != nil {
+		return entity.SheetSnapshot{}, nil, fmt.Errorf("error decompressing sheet snapshot %s: %w", id, err)
+	}
+	records, err := io.ReadAll(reader)
+	if err != nil {
+		return entity.SheetSnapshot{}, nil, fmt.Errorf("error decompressing sheet snapshot %s: %w", id, err)
+	}
+	sum := sha256.Sum256(records)
+	if hex.EncodeToString(sum[:]) != snapshot.Checksum {
+		return entity.SheetSnapshot{}, nil, fmt.Errorf("sheet snapshot %s does not match its checksum", id)
+	}
+	snapshot.Data = nil
+	return snapshot, records, nil
+}
+
+func (s *SheetSnapshotService) List(ctx context.Context, query entity.SheetSnapshotQuery) (entity.SheetSnapshotPage, error) {
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = defaultPageSize
+	}
+	query.PageSize = min(query.PageSize, maxPageSize)
+	snapshots, total, err := s.repository.Find(ctx, query)
+	if err != nil {
+		return entity.SheetSnapshotPage{}, err
+	}
+	return entity.SheetSnapshotPage{
+		Items:    snapshots,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
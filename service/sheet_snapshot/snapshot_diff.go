@@ -0,0 +1,100 @@
+package sheet_snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+// Diff compares two snapshots of the same worksheet record by record.
+func (s *SheetSnapshotService) Diff(ctx context.Context, fromId string, toId string) (entity.SnapshotDiff, error) {
+	from, fromRecords, err := s.Load(ctx, fromId)
+	if err != nil {
+		return entity.SnapshotDiff{}, err
+	}
+	to, toRecords, err := s.Load(ctx, toId)
+	if err != nil {
+		return entity.SnapshotDiff{}, err
+	}
+	if from.SheetId != to.SheetId || from.Worksheet != to.Worksheet {
+		return entity.SnapshotDiff{}, &entity.InvalidSnapshotError{
+			Message: fmt.Sprintf("snapshots are of different worksheets: %s and %s", from.Worksheet, to.Worksheet),
+		}
+	}
+	fromRows, fromKeys, err := keyedRecords(fromRecords)
+	if err != nil {
+		return entity.SnapshotDiff{}, fmt.Errorf("error reading sheet snapshot %s: %w", fromId, err)
+	}
+	toRows, toKeys, err := keyedRecords(toRecords)
+	if err != nil {
+		return entity.SnapshotDiff{}, fmt.Errorf("error reading sheet snapshot %s: %w", toId, err)
+	}
+
+	diff := entity.SnapshotDiff{
+		Worksheet: from.Worksheet,
+		From:      fromId,
+		To:        toId,
+		Added:     []string{},
+		Removed:   []string{},
+		Changed:   []entity.RecordChange{},
+	}
+	for _, key := range toKeys {
+		old, ok := fromRows[key]
+		if !ok {
+			diff.Added = append(diff.Added, key)
+			continue
+		}
+		if columns := changedColumns(old, toRows[key]); len(columns) > 0 {
+			diff.Changed = append(diff.Changed, entity.RecordChange{Key: key, Columns: columns})
+		}
+	}
+	for _, key := range fromKeys {
+		if _, ok := toRows[key]; !ok {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+	return diff, nil
+}
+
+// keyedRecords indexes the records by their ID column, or by row index when there is none,
+// and returns the keys in sheet order. Repeated IDs are told apart by their row index.
+func keyedRecords(records json.RawMessage) (map[string]map[string]interface{}, []string, error) {
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(records, &rows); err != nil {
+		return nil, nil, err
+	}
+	keyed := make(map[string]map[string]interface{}, len(rows))
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		key := fmt.Sprintf("row %v", row["row_index"])
+		if id, ok := row["ID"]; ok && id != nil && id != "" {
+			key = fmt.Sprint(id)
+			if _, seen := keyed[key]; seen {
+				key = fmt.Sprintf("%s (row %v)", key, row["row_index"])
+			}
+		}
+		keyed[key] = row
+		keys = append(keys, key)
+	}
+	return keyed, keys, nil
+}
+
+func changedColumns(old map[string]interface{}, new map[string]interface{}) []string {
+	var columns []string
+	for column, value := range new {
+		if !reflect.DeepEqual(old[column], value) {
+			columns = append(columns, column)
+		}
+	}
+	for column := range old {
+		if _, ok := new[column]; !ok {
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
 	mongoRepo "github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/prarthana_data"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/zoho"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
 	"go.uber.org/zap"
 )
 
@@ -31,7 +32,9 @@ func InitShlokIngestionService(ctx context.Context,
 	}
 }
 
-func (s *ShlokIngestionService) ShlokIngestion(ctx context.Context, startID, endID int) error {
+// ShlokIngestion ingests the shloks with IDs in range. Incrementally, rows whose source hash
+// matches the stored shlok are skipped.
+func (s *ShlokIngestionService) ShlokIngestion(ctx context.Context, startID, endID int, incremental bool) error {
 	var response entity.ShlokaSheetResponse
 	err := s.zohoService.GetSheetData(ctx, "shloka", &response)
 	if err != nil {
@@ -40,8 +43,15 @@ func (s *ShlokIngestionService) ShlokIngestion(ctx context.Context, startID, end
 	if len(response.Records) == 0 {
 		return errors.New("no records found")
 	}
+	var storedHashes map[string]string
+	if incremental {
+		if storedHashes, err = s.prarthanaMongoRepository.GetSourceHashes(ctx, entity.ContentShloks); err != nil {
+			return err
+		}
+	}
 
 	var shloks []entity.Shlok
+	skipped := 0
 	langs := []string{"sanskrit", "english", "kannada", "hindi", "telugu", "bengali", "marathi", "tamil", "gujarati", "odiya", "malayalam", "assamese", "punjabi"}
 	for i, record := range response.Records {
 		log.Printf("Processing record %d\n", i+1) // Log the current record number
@@ -53,6 +63,13 @@ func (s *ShlokIngestionService) ShlokIngestion(ctx context.Context, startID, end
 		if id < startID || id > endID {
 			continue
 		}
+		sourceHash := util.SourceHash(record)
+		if incremental && storedHashes[strconv.Itoa(id)] == sourceHash {
+			log.Printf("Skipping unchanged shlok %d\n", id)
+			util.RecordChange(ctx, entity.ContentShloks, strconv.Itoa(id), entity.ChangeSkipped, nil)
+			skipped++
+			continue
+		}
 		name, ok := record["Name (Optional)"].(string)
 		if !ok {
 			name = ""
@@ -65,12 +82,13 @@ func (s *ShlokIngestionService) ShlokIngestion(ctx context.Context, startID, end
 			},
 			Explanation: make(map[string]string),
 			Shlok:       make(map[string]string),
+			SourceHash:  sourceHash,
 		}
 
 		for _, lang := range langs {
 			value, exists := record[fmt.Sprintf("translation_%s", lang)].(string)
 			if !exists || value == "" {
-				log.Printf("Warning: Missing translation for language '%s' in record %d\n", lang, i+1)
+				util.RecordWarning(ctx, entity.ContentShloks, "Missing translation for language '%s' in record %d", lang, i+1)
 				continue
 			}
 
@@ -84,7 +102,7 @@ func (s *ShlokIngestionService) ShlokIngestion(ctx context.Context, startID, end
 		for _, lang := range langs {
 			value, exists := record[fmt.Sprintf("text_%s", lang)].(string)
 			if !exists || value == "" {
-				log.Printf("Warning: Missing shlok for language '%s' in record %d\n", lang, i+1)
+				util.RecordWarning(ctx, entity.ContentShloks, "Missing shlok for language '%s' in record %d", lang, i+1)
 				continue
 			}
 
@@ -97,6 +115,9 @@ func (s *ShlokIngestionService) ShlokIngestion(ctx context.Context, startID, end
 		shloks = append(shloks, shlok)
 	}
 	if len(shloks) == 0 {
+		if skipped > 0 {
+			return nil
+		}
 		return errors.New("no shloks to ingest")
 	}
 	return s.prarthanaMongoRepository.InsertManyShloks(ctx, shloks)
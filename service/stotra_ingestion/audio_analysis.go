@@ -0,0 +1,89 @@
+package stotra_ingestion
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_url"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+)
+
+func decodeAudioFile(filename string) (*util.PCM, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return util.DecodeAudio(data, filepath.Ext(filename))
+}
+
+// analyzeAudio measures the loudness of a stotra recording and flags every measure outside
+// the configured limits.
+func (s *StotraIngestionService) analyzeAudio(pcm *util.PCM) *entity.AudioAnalysis {
+	stats := util.AnalyzeLoudness(pcm)
+	analysis := &entity.AudioAnalysis{
+		IntegratedLufs:    stats.IntegratedLufs,
+		PeakDbfs:          stats.PeakDbfs,
+		LeadingSilenceMs:  stats.LeadingSilenceMs,
+		TrailingSilenceMs: stats.TrailingSilenceMs,
+		ClippedSamples:    stats.ClippedSamples,
+	}
+
+	limits := s.loudnessConfig
+	if diff := stats.IntegratedLufs - limits.TargetLufs; diff > limits.ToleranceLu || -diff > limits.ToleranceLu {
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("loudness %.1f LUFS is %+.1f LU from the %.1f LUFS target", stats.IntegratedLufs, diff, limits.TargetLufs))
+	}
+	if stats.PeakDbfs > limits.MaxPeakDbfs {
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("peak %.1f dBFS exceeds %.1f dBFS", stats.PeakDbfs, limits.MaxPeakDbfs))
+	}
+	if limits.MaxSilenceMs > 0 && stats.LeadingSilenceMs > limits.MaxSilenceMs {
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("%dms of leading silence", stats.LeadingSilenceMs))
+	}
+	if limits.MaxSilenceMs > 0 && stats.TrailingSilenceMs > limits.MaxSilenceMs {
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("%dms of trailing silence", stats.TrailingSilenceMs))
+	}
+	if stats.ClippedSamples > limits.MaxClippedSamples {
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("%d clipped samples", stats.ClippedSamples))
+	}
+	return analysis
+}
+
+// saveWaveform stores the peaks of the recording next to it, named after the audio file,
+// and returns their URL, or "" when waveforms are disabled or could not be stored.
+func (s *StotraIngestionService) saveWaveform(ctx context.Context, audioUrl string, pcm *util.PCM) string {
+	if !s.waveformService.Enabled() {
+		return ""
+	}
+	builder := util.NewWaveformBuilder(pcm.SampleRate, pcm.Channels)
+	builder.Add(pcm.Samples)
+	url, err := s.waveformService.Save(ctx, asset_url.StotraWaveform, audioBaseName(audioUrl), builder)
+	if err != nil {
+		log.Printf("Error saving waveform of %s: %v\n", audioUrl, err)
+		return ""
+	}
+	return url
+}
+
+// transcodeAudio encodes the downloaded recording into the bitrate ladder, leaving the stotra
+// without renditions when transcoding is disabled or fails.
+func (s *StotraIngestionService) transcodeAudio(ctx context.Context, audioUrl string, filename string) entity.TranscodedAudio {
+	if !s.transcodingService.Enabled() {
+		return entity.TranscodedAudio{}
+	}
+	transcoded, err := s.transcodingService.Transcode(ctx, "stotras/"+audioBaseName(audioUrl), filename)
+	if err != nil {
+		log.Printf("Error transcoding %s: %v\n", audioUrl, err)
+		return entity.TranscodedAudio{}
+	}
+	return transcoded
+}
+
+// audioBaseName is the file name of the audio without its extension.
+func audioBaseName(audioUrl string) string {
+	name := path.Base(audioUrl)
+	return name[:len(name)-len(path.Ext(name))]
+}
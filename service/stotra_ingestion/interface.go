@@ -6,5 +6,5 @@ import (
 )
 
 type Service interface {
-	StotraIngestion(ctx context.Context, startID, endID int) (map[string]entity.Stotra, error)
+	StotraIngestion(ctx context.Context, startID, endID int, incremental bool) (map[string]entity.Stotra, error)
 }
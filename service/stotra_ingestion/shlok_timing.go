@@ -0,0 +1,102 @@
+package stotra_ingestion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+)
+
+const (
+	shlokTimingSourceSheet    = "sheet"
+	shlokTimingSourceDetected = "detected"
+)
+
+// shlokTimings maps each shlok of the stotra to its time range in the audio. Start times
+// entered in the sheet take precedence and must match the shloks one to one; otherwise the
+// boundaries are placed in the longest pauses of the audio, and a stotra without enough
+// pauses is left without timings.
+func (s *StotraIngestionService) shlokTimings(ctx context.Context, id int, record map[string]interface{}, shlokIds []string, pcm *util.PCM, durationMs int) ([]entity.ShlokTiming, error) {
+	if len(shlokIds) == 0 {
+		return nil, nil
+	}
+	if column, ok := record["Shloka Timestamps (Comma separated - Ordered)"].(string); ok && strings.TrimSpace(column) != "" {
+		starts, err := parseShlokTimestamps(column, durationMs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shloka timestamps for row %d: %w", id, err)
+		}
+		if len(starts) != len(shlokIds) {
+			return nil, fmt.Errorf("row %d has %d shloka timestamps for %d shlokas", id, len(starts), len(shlokIds))
+		}
+		return buildShlokTimings(shlokIds, starts, durationMs, shlokTimingSourceSheet), nil
+	}
+	if pcm == nil {
+		return nil, nil
+	}
+
+	pauses := util.DetectPauses(pcm, s.alignmentConfig.SilenceThresholdDbfs, s.alignmentConfig.MinPauseMs)
+	if len(pauses) < len(shlokIds)-1 {
+		util.RecordWarning(ctx, entity.ContentStotras, "row %d: found %d pauses for %d shlokas, shloka timings not set", id, len(pauses), len(shlokIds))
+		return nil, nil
+	}
+	sort.SliceStable(pauses, func(i, j int) bool { return pauses[i].DurationMs() > pauses[j].DurationMs() })
+	pauses = pauses[:len(shlokIds)-1]
+	sort.Slice(pauses, func(i, j int) bool { return pauses[i].StartMs < pauses[j].StartMs })
+	starts := []int{0}
+	for _, pause := range pauses {
+		starts = append(starts, pause.MidpointMs())
+	}
+	return buildShlokTimings(shlokIds, starts, durationMs, shlokTimingSourceDetected), nil
+}
+
+func buildShlokTimings(shlokIds []string, starts []int, durationMs int, source string) []entity.ShlokTiming {
+	timings := make([]entity.ShlokTiming, len(shlokIds))
+	for i, shlokId := range shlokIds {
+		end := durationMs
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		timings[i] = entity.ShlokTiming{ShlokId: shlokId, StartMs: starts[i], EndMs: end, Source: source}
+	}
+	return timings
+}
+
+// parseShlokTimestamps parses comma separated start times given as seconds ("12.5") or
+// [h:]m:s ("1:02.5"); they must increase and fall inside the audio.
+func parseShlokTimestamps(column string, durationMs int) ([]int, error) {
+	var starts []int
+	for _, value := range util.GetSplittedString(column) {
+		ms, err := parseTimestampMs(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(starts) > 0 && ms <= starts[len(starts)-1] {
+			return nil, fmt.Errorf("timestamp %s is not after the previous one", value)
+		}
+		if ms >= durationMs {
+			return nil, fmt.Errorf("timestamp %s is beyond the audio duration of %dms", value, durationMs)
+		}
+		starts = append(starts, ms)
+	}
+	return starts, nil
+}
+
+func parseTimestampMs(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %s", value)
+	}
+	seconds := 0.0
+	for _, part := range parts {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp %s", value)
+		}
+		seconds = seconds*60 + n
+	}
+	return int(seconds * 1000), nil
+}
@@ -5,8 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
 	mongoRepo "github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/mongo/prarthana_data"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_url"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_verifier"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/search_indexing"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/transcoding"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/waveform"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/zoho"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
 	"github.com/go-audio/wav"
@@ -29,16 +35,36 @@ type StotraIngestionService struct {
 	logger                   *zap.Logger
 	prarthanaMongoRepository mongoRepo.MongoRepository
 	zohoService              zoho.Service
+	searchIndexingService    search_indexing.Service
+	assetUrlService          asset_url.Service
+	assetVerifierService     asset_verifier.Service
+	waveformService          waveform.Service
+	transcodingService       transcoding.Service
+	loudnessConfig           configuration.LoudnessConfig
+	alignmentConfig          configuration.AlignmentConfig
 }
 
 func InitStotraIngestionService(ctx context.Context,
+	configuration *configuration.Configuration,
 	prarthanaMongoRepository mongoRepo.MongoRepository,
 	zohoService zoho.Service,
+	searchIndexingService search_indexing.Service,
+	assetUrlService asset_url.Service,
+	assetVerifierService asset_verifier.Service,
+	waveformService waveform.Service,
+	transcodingService transcoding.Service,
 ) *StotraIngestionService {
 	return &StotraIngestionService{
 		logger:                   logging.WithContext(ctx),
 		prarthanaMongoRepository: prarthanaMongoRepository,
 		zohoService:              zohoService,
+		searchIndexingService:    searchIndexingService,
+		assetUrlService:          assetUrlService,
+		assetVerifierService:     assetVerifierService,
+		waveformService:          waveformService,
+		transcodingService:       transcodingService,
+		loudnessConfig:           configuration.LoudnessConfig,
+		alignmentConfig:          configuration.AlignmentConfig,
 	}
 }
 
@@ -139,7 +165,10 @@ func InitStotraIngestionService(ctx context.Context,
 //	return stotraMap, s.prarthanaMongoRepository.InsertManyStotras(ctx, stotras)
 //}
 
-func (s *StotraIngestionService) StotraIngestion(ctx context.Context, startID, endID int) (map[string]entity.Stotra, error) {
+// StotraIngestion ingests the stotras with IDs in range. The audio of every row in range is
+// verified first, as its ETag and Last-Modified are part of the source hash; incrementally,
+// rows whose hash matches the stored stotra are skipped before their audio is downloaded.
+func (s *StotraIngestionService) StotraIngestion(ctx context.Context, startID, endID int, incremental bool) (map[string]entity.Stotra, error) {
 	var response entity.ShlokaSheetResponse
 	err := s.zohoService.GetSheetData(ctx, "stotra", &response)
 	if err != nil {
@@ -148,6 +177,15 @@ func (s *StotraIngestionService) StotraIngestion(ctx context.Context, startID, e
 	if len(response.Records) == 0 {
 		return nil, errors.New("no records found")
 	}
+	audio, err := s.resolveAudio(ctx, response.Records, startID, endID)
+	if err != nil {
+		return nil, err
+	}
+
+	records, sourceHashes, err := s.changedRecords(ctx, response.Records, audio, startID, endID, incremental)
+	if err != nil {
+		return nil, err
+	}
 
 	stotraMap := make(map[string]entity.Stotra)
 	var mu sync.Mutex
@@ -155,7 +193,7 @@ func (s *StotraIngestionService) StotraIngestion(ctx context.Context, startID, e
 	errChan := make(chan error, 1)
 	sem := make(chan struct{}, 10)
 
-	for i, record := range response.Records {
+	for i, record := range records {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
@@ -186,11 +224,6 @@ func (s *StotraIngestionService) StotraIngestion(ctx context.Context, startID, e
 					return
 				}
 				nameDefault = strings.TrimSpace(nameDefault)
-				re := regexp.MustCompile(`[^a-zA-Z0-9\s\-]+`)
-				if re.MatchString(nameDefault) {
-					errChan <- fmt.Errorf("the name '%s' contains special characters. Please remove them", nameDefault)
-					return
-				}
 				nameHindi, ok := record["Name (Optional) (Hindi)"].(string)
 				nameKannada, ok := record["Name (Optional) (Kannada)"].(string)
 				nameMarathi, ok := record["Name (Optional) (Marathi)"].(string)
@@ -198,19 +231,8 @@ func (s *StotraIngestionService) StotraIngestion(ctx context.Context, startID, e
 				nameTelugu, ok := record["Name (Optional) (Telugu)"].(string)
 				nameGujarati, ok := record["Name (Optional) (Gujarati)"].(string)
 
-				baseFilename := strings.ToLower(util.SanitizeString(nameDefault))
-				//strings.ToLower(strings.ReplaceAll(strings.TrimSuffix(name, "|"), " ", "_"))
-				isWav := true
-				stotraUrl := "https://d161fa2zahtt3z.cloudfront.net/audio/" + baseFilename + ".wav"
-				stotraUrlmp3 := "https://d161fa2zahtt3z.cloudfront.net/audio/" + baseFilename + ".mp3"
-				if !util.UrlExists(stotraUrl) {
-					if !util.UrlExists(stotraUrlmp3) {
-						errChan <- fmt.Errorf("audio URL does not exist: %s", stotraUrl)
-						return
-					}
-					isWav = false
-					stotraUrl = stotraUrlmp3
-				}
+				stotraUrl := audio[id].Url
+				isWav := strings.HasSuffix(stotraUrl, ".wav")
 
 				resp, err := http.Get(stotraUrl)
 				if err != nil || resp.StatusCode != http.StatusOK {
@@ -248,7 +270,29 @@ func (s *StotraIngestionService) StotraIngestion(ctx context.Context, startID, e
 					return
 				}
 
-				shlokIds := fmt.Sprintf("%v", record["Shloka ID (Comma separated - Ordered)"])
+				var audioAnalysis *entity.AudioAnalysis
+				var waveformUrl string
+				pcm, err := decodeAudioFile(tempFile.Name())
+				if err != nil {
+					log.Printf("Error decoding audio of row %d: %v\n", id, err)
+				} else {
+					audioAnalysis = s.analyzeAudio(pcm)
+					if len(audioAnalysis.Warnings) > 0 {
+						util.RecordWarning(ctx, entity.ContentStotras, "audio of row %d: %s", id, strings.Join(audioAnalysis.Warnings, "; "))
+					}
+					waveformUrl = s.saveWaveform(ctx, stotraUrl, pcm)
+				}
+				transcoded := s.transcodeAudio(ctx, stotraUrl, tempFile.Name())
+
+				shlokIds := util.GetSplittedString(fmt.Sprintf("%v", record["Shloka ID (Comma separated - Ordered)"]))
+				shlokTimings, err := s.shlokTimings(ctx, id, record, shlokIds, pcm, durationInMilliseconds)
+				if err != nil {
+					select {
+					case errChan <- err:
+					default:
+					}
+					return
+				}
 				stotra := entity.Stotra{
 					ID:    strconv.Itoa(id),
 					IntId: id,
@@ -261,11 +305,17 @@ func (s *StotraIngestionService) StotraIngestion(ctx context.Context, startID, e
 						"te":      nameTelugu,
 						"gu":      nameGujarati,
 					},
-					ShlokIds:               util.GetSplittedString(shlokIds),
+					ShlokIds:               shlokIds,
 					Duration:               durationStr,
 					DurationInSeconds:      durationInSeconds,
 					DurationInMilliseconds: durationInMilliseconds,
 					StotraUrl:              stotraUrl,
+					AudioAnalysis:          audioAnalysis,
+					ShlokTimings:           shlokTimings,
+					WaveformUrl:            waveformUrl,
+					HlsUrl:                 transcoded.HlsUrl,
+					Renditions:             transcoded.Renditions,
+					SourceHash:             sourceHashes[id],
 				}
 
 				mu.Lock()
@@ -291,7 +341,94 @@ func (s *StotraIngestionService) StotraIngestion(ctx context.Context, startID, e
 	for _, stotra := range stotraMap {
 		stotras = append(stotras, stotra)
 	}
-	return stotraMap, s.prarthanaMongoRepository.InsertManyStotras(ctx, stotras)
+	if err := s.prarthanaMongoRepository.InsertManyStotras(ctx, stotras); err != nil {
+		return nil, err
+	}
+	if err := s.searchIndexingService.IndexStotras(ctx, stotras); err != nil {
+		log.Printf("Failed to index stotras for search: %v\n", err)
+	}
+	return stotraMap, nil
+}
+
+// changedRecords returns the records in range that need to be ingested, with their source
+// hashes by ID. The hash covers the row, the validators of its verified audio and whether
+// waveforms and transcoding are enabled, so replacing the audio or turning either on
+// re-ingests the stotra. Incrementally, records whose hash matches the stored stotra are
+// left out.
+func (s *StotraIngestionService) changedRecords(ctx context.Context, records []map[string]interface{}, audio map[int]entity.AssetCheckResult, startID, endID int, incremental bool) ([]map[string]interface{}, map[int]string, error) {
+	var storedHashes map[string]string
+	if incremental {
+		var err error
+		if storedHashes, err = s.prarthanaMongoRepository.GetSourceHashes(ctx, entity.ContentStotras); err != nil {
+			return nil, nil, err
+		}
+	}
+	var changed []map[string]interface{}
+	sourceHashes := make(map[int]string)
+	for _, record := range records {
+		idf, ok := record["ID"].(float64)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid ID")
+		}
+		id := int(idf)
+		if id < startID || id > endID {
+			continue
+		}
+		sourceHash := util.SourceHash(record, audio[id].ETag, audio[id].LastModified, s.waveformService.Enabled(), s.transcodingService.Enabled())
+		if incremental && storedHashes[strconv.Itoa(id)] == sourceHash {
+			log.Printf("Skipping unchanged stotra %d\n", id)
+			util.RecordChange(ctx, entity.ContentStotras, strconv.Itoa(id), entity.ChangeSkipped, nil)
+			continue
+		}
+		sourceHashes[id] = sourceHash
+		changed = append(changed, record)
+	}
+	return changed, sourceHashes, nil
+}
+
+// resolveAudio validates the names of the rows in range and verifies their audio in one
+// batch, returning the result for the .wav (or fallback .mp3) of every row.
+func (s *StotraIngestionService) resolveAudio(ctx context.Context, records []map[string]interface{}, startID, endID int) (map[int]entity.AssetCheckResult, error) {
+	re := regexp.MustCompile(`[^a-zA-Z0-9\s\-]+`)
+	var checks []entity.AssetCheck
+	for _, record := range records {
+		idf, ok := record["ID"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("invalid ID")
+		}
+		id := int(idf)
+		if id < startID || id > endID {
+			continue
+		}
+		nameDefault, ok := record["Name (Optional) (Default)"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid Name : %d", id)
+		}
+		nameDefault = strings.TrimSpace(nameDefault)
+		if re.MatchString(nameDefault) {
+			return nil, fmt.Errorf("the name '%s' contains special characters. Please remove them", nameDefault)
+		}
+		baseFilename := strings.ToLower(util.SanitizeString(nameDefault))
+		checks = append(checks, entity.AssetCheck{
+			Row:  id,
+			Kind: asset_url.StotraAudio,
+			Candidates: []string{
+				s.assetUrlService.Url(asset_url.StotraAudio, baseFilename+".wav"),
+				s.assetUrlService.Url(asset_url.StotraAudio, baseFilename+".mp3"),
+			},
+		})
+	}
+
+	// the validators of the results feed the source hash, so they must not come from the cache
+	results, missingErr := s.assetVerifierService.VerifyFresh(ctx, "stotra", checks)
+	if missingErr != nil {
+		return nil, missingErr
+	}
+	audio := make(map[int]entity.AssetCheckResult)
+	for _, result := range results {
+		audio[result.Row] = result
+	}
+	return audio, nil
 }
 
 func getDurationFromFile(filename string) (string, int, error) {
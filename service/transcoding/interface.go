@@ -0,0 +1,11 @@
+package transcoding
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+type Service interface {
+	Enabled() bool
+	Transcode(ctx context.Context, name string, input string) (entity.TranscodedAudio, error)
+}
@@ -0,0 +1,172 @@
+package transcoding
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/storage"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_url"
+	"go.uber.org/zap"
+)
+
+const masterPlaylist = "master.m3u8"
+
+type codec struct {
+	encoder   string
+	extension string
+	// RFC 6381 codec string for the master playlist
+	hlsCodec string
+}
+
+var codecs = map[string]codec{
+	"aac": {encoder: "aac", extension: ".m4a", hlsCodec: "mp4a.40.2"},
+	"mp3": {encoder: "libmp3lame", extension: ".mp3", hlsCodec: "mp4a.40.34"},
+}
+
+var contentTypes = map[string]string{
+	".m3u8": "application/vnd.apple.mpegurl",
+	".ts":   "video/mp2t",
+	".m4a":  "audio/mp4",
+	".mp3":  "audio/mpeg",
+}
+
+type TranscodingService struct {
+	logger          *zap.Logger
+	config          configuration.TranscodingConfig
+	storage         storage.Storage
+	assetUrlService asset_url.Service
+}
+
+func InitTranscodingService(ctx context.Context,
+	configuration *configuration.Configuration,
+	storage storage.Storage,
+	assetUrlService asset_url.Service,
+) *TranscodingService {
+	config := configuration.TranscodingConfig
+	if config.FfmpegPath == "" {
+		config.FfmpegPath = "ffmpeg"
+	}
+	if config.HlsSegmentSeconds <= 0 {
+		config.HlsSegmentSeconds = 10
+	}
+	return &TranscodingService{
+		logger:          logging.WithContext(ctx),
+		config:          config,
+		storage:         storage,
+		assetUrlService: assetUrlService,
+	}
+}
+
+func (s *TranscodingService) Enabled() bool {
+	return s.config.Enabled && len(s.config.Renditions) > 0
+}
+
+// Transcode encodes the input file into every rendition of the ladder, each as a progressive
+// file and an HLS playlist, writes a master playlist over them and uploads everything under
+// <name>. Name may contain a directory, e.g. "stotras/ganesh_stotra".
+func (s *TranscodingService) Transcode(ctx context.Context, name string, input string) (entity.TranscodedAudio, error) {
+	dir, err := os.MkdirTemp("", "transcode-*")
+	if err != nil {
+		return entity.TranscodedAudio{}, fmt.Errorf("error creating transcoding directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	hlsDir := filepath.Join(dir, "hls")
+	if err := os.Mkdir(hlsDir, 0o755); err != nil {
+		return entity.TranscodedAudio{}, fmt.Errorf("error creating transcoding directory: %w", err)
+	}
+
+	var transcoded entity.TranscodedAudio
+	var master strings.Builder
+	master.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, rendition := range s.config.Renditions {
+		codec, ok := codecs[strings.ToLower(rendition.Codec)]
+		if !ok {
+			return entity.TranscodedAudio{}, fmt.Errorf("unsupported codec %s", rendition.Codec)
+		}
+		variant := fmt.Sprintf("%s_%dk", strings.ToLower(rendition.Codec), rendition.BitrateKbps)
+		progressive := filepath.Join(dir, variant+codec.extension)
+		if err := s.ffmpeg(ctx, input, codec, rendition.BitrateKbps, progressive, hlsDir, variant); err != nil {
+			return entity.TranscodedAudio{}, err
+		}
+
+		progressiveName := path.Base(name) + "_" + variant + codec.extension
+		if err := s.upload(ctx, progressive, s.assetUrlService.Path(asset_url.AudioRendition, path.Join(path.Dir(name), progressiveName))); err != nil {
+			return entity.TranscodedAudio{}, err
+		}
+		transcoded.Renditions = append(transcoded.Renditions, entity.AudioRendition{
+			Codec:       strings.ToLower(rendition.Codec),
+			BitrateKbps: rendition.BitrateKbps,
+			Url:         s.assetUrlService.Url(asset_url.AudioRendition, path.Join(path.Dir(name), progressiveName)),
+			HlsUrl:      s.assetUrlService.Url(asset_url.AudioHls, path.Join(name, variant+".m3u8")),
+		})
+		master.WriteString(fmt.Sprintf("#EXT-X-STREAM-INF:BANDWIDTH=%d,CODECS=\"%s\"\n%s.m3u8\n", rendition.BitrateKbps*1000, codec.hlsCodec, variant))
+	}
+	if err := os.WriteFile(filepath.Join(hlsDir, masterPlaylist), []byte(master.String()), 0o644); err != nil {
+		return entity.TranscodedAudio{}, fmt.Errorf("error writing master playlist: %w", err)
+	}
+
+	entries, err := os.ReadDir(hlsDir)
+	if err != nil {
+		return entity.TranscodedAudio{}, fmt.Errorf("error reading HLS output: %w", err)
+	}
+	for _, entry := range entries {
+		key := s.assetUrlService.Path(asset_url.AudioHls, path.Join(name, entry.Name()))
+		if err := s.upload(ctx, filepath.Join(hlsDir, entry.Name()), key); err != nil {
+			return entity.TranscodedAudio{}, err
+		}
+	}
+	transcoded.HlsUrl = s.assetUrlService.Url(asset_url.AudioHls, path.Join(name, masterPlaylist))
+	return transcoded, nil
+}
+
+// ffmpeg encodes one rendition to the progressive file and to <variant>.m3u8 with its
+// segments in hlsDir, in a single pass over the input.
+func (s *TranscodingService) ffmpeg(ctx context.Context, input string, codec codec, bitrateKbps int, progressive string, hlsDir string, variant string) error {
+	bitrate := strconv.Itoa(bitrateKbps) + "k"
+	args := []string{"-hide_banner", "-loglevel", "error", "-y", "-i", input,
+		"-vn", "-c:a", codec.encoder, "-b:a", bitrate, progressive,
+		"-vn", "-c:a", codec.encoder, "-b:a", bitrate,
+		"-f", "hls",
+		"-hls_time", strconv.Itoa(s.config.HlsSegmentSeconds),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(hlsDir, variant+"_%03d.ts"),
+		filepath.Join(hlsDir, variant+".m3u8"),
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.config.FfmpegPath, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error transcoding %s to %s: %w: %s", input, variant, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (s *TranscodingService) upload(ctx context.Context, filename string, key string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", filename, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", filename, err)
+	}
+	contentType, ok := contentTypes[filepath.Ext(filename)]
+	if !ok {
+		contentType = "application/octet-stream"
+	}
+	if err := s.storage.Put(ctx, key, contentType, file, info.Size()); err != nil {
+		return fmt.Errorf("error uploading %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package waveform
+
+import (
+	"context"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+)
+
+type Service interface {
+	Enabled() bool
+	Save(ctx context.Context, kind string, name string, builder *util.WaveformBuilder) (string, error)
+}
@@ -0,0 +1,57 @@
+package waveform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/repository/storage"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/asset_url"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
+	"go.uber.org/zap"
+)
+
+type WaveformService struct {
+	logger          *zap.Logger
+	config          configuration.WaveformConfig
+	storage         storage.Storage
+	assetUrlService asset_url.Service
+}
+
+func InitWaveformService(ctx context.Context,
+	configuration *configuration.Configuration,
+	storage storage.Storage,
+	assetUrlService asset_url.Service,
+) *WaveformService {
+	config := configuration.WaveformConfig
+	if config.Points <= 0 {
+		config.Points = 1000
+	}
+	return &WaveformService{
+		logger:          logging.WithContext(ctx),
+		config:          config,
+		storage:         storage,
+		assetUrlService: assetUrlService,
+	}
+}
+
+func (s *WaveformService) Enabled() bool {
+	return s.config.Enabled
+}
+
+// Save writes the peaks accumulated by the builder, reduced to the configured number of
+// points, as <name>.json of the waveform kind and returns its URL.
+func (s *WaveformService) Save(ctx context.Context, kind string, name string, builder *util.WaveformBuilder) (string, error) {
+	data, err := json.Marshal(builder.Build(s.config.Points))
+	if err != nil {
+		return "", fmt.Errorf("error encoding waveform %s: %w", name, err)
+	}
+	key := s.assetUrlService.Path(kind, name)
+	if err := s.storage.Put(ctx, key, "application/json", bytes.NewReader(data), int64(len(data))); err != nil {
+		return "", fmt.Errorf("error uploading waveform %s: %w", key, err)
+	}
+	return s.assetUrlService.Url(kind, name), nil
+}
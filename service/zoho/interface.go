@@ -5,6 +5,5 @@ import (
 )
 
 type Service interface {
-	RefreshAccessToken() (string, error)
 	GetSheetData(ctx context.Context, sheetName string, response interface{}) error
 }
@@ -6,77 +6,101 @@ import (
 	"fmt"
 	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/sheet_snapshot"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/service/zoho_token"
 	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/util"
 	"go.uber.org/zap"
 	"io/ioutil"
+	"log"
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 )
 
-type ZohoService struct {
-	logger        *zap.Logger
-	configuration *configuration.Configuration
-	httpClient    *http.Client
-	tokenManager  TokenManager
-}
-
-type TokenResponse struct {
-	AccessToken string `json:"access_token"`
-	ExpiresIn   int    `json:"expires_in"`
+// sheetRecords is the part of a worksheet.records.fetch response that is snapshotted.
+type sheetRecords struct {
+	Records json.RawMessage `json:"records"`
 }
 
-type TokenManager struct {
-	AccessToken  string
-	RefreshToken string
-	ExpiresAt    time.Time
+type ZohoService struct {
+	logger               *zap.Logger
+	configuration        *configuration.Configuration
+	httpClient           *http.Client
+	zohoTokenService     zoho_token.Service
+	sheetSnapshotService sheet_snapshot.Service
 }
 
 func InitZohoService(ctx context.Context,
 	configuration *configuration.Configuration,
 	httpClient *http.Client,
+	zohoTokenService zoho_token.Service,
+	sheetSnapshotService sheet_snapshot.Service,
 ) *ZohoService {
 	return &ZohoService{
-		logger:        logging.WithContext(ctx),
-		configuration: configuration,
-		httpClient:    httpClient,
+		logger:               logging.WithContext(ctx),
+		configuration:        configuration,
+		httpClient:           httpClient,
+		zohoTokenService:     zohoTokenService,
+		sheetSnapshotService: sheetSnapshotService,
 	}
 }
 
-func (s *ZohoService) RefreshAccessToken() (string, error) {
-	if s.configuration.ZohoConfig.RefreshToken == "" {
-		return "", fmt.Errorf("refresh token not set")
+// GetSheetData reads the records of a worksheet into response. Worksheets pinned to a
+// snapshot in the context are read from the snapshot; live reads are snapshotted when
+// snapshots are enabled. Either way the snapshot used is added to the audit trail.
+func (s *ZohoService) GetSheetData(ctx context.Context, sheetName string, response interface{}) error {
+	sheetId := s.configuration.ZohoConfig.SheetId
+	var records json.RawMessage
+	var snapshotId string
+	if pinned, ok := util.GetSheetSnapshotsFromContext(ctx)[sheetName]; ok {
+		snapshot, data, err := s.sheetSnapshotService.Load(ctx, pinned)
+		if err != nil {
+			return fmt.Errorf("error loading snapshot %s of %s: %w", pinned, sheetName, err)
+		}
+		if snapshot.Worksheet != sheetName {
+			return &entity.InvalidSnapshotError{Message: fmt.Sprintf("snapshot %s is of worksheet %s, not %s", pinned, snapshot.Worksheet, sheetName)}
+		}
+		sheetId, records, snapshotId = snapshot.SheetId, data, snapshot.Id
+	} else {
+		data, err := s.fetchRecords(ctx, sheetName)
+		if err != nil {
+			return err
+		}
+		records = data
+		if s.sheetSnapshotService.Enabled() {
+			snapshot, err := s.sheetSnapshotService.Save(ctx, sheetId, sheetName, records)
+			if err != nil {
+				// the ingestion itself does not depend on the snapshot
+				log.Printf("Error saving snapshot of %s: %v\n", sheetName, err)
+			}
+			snapshotId = snapshot.Id
+		}
 	}
 
-	data := url.Values{}
-	data.Set("grant_type", "refresh_token")
-	data.Set("refresh_token", s.configuration.ZohoConfig.RefreshToken)
-	data.Set("client_id", s.configuration.ZohoConfig.ClientId)
-	data.Set("client_secret", s.configuration.ZohoConfig.ClientSecret)
-
-	resp, err := http.PostForm(s.configuration.ZohoConfig.TokenUrl, data)
+	body, err := json.Marshal(sheetRecords{Records: records})
 	if err != nil {
-		return "", fmt.Errorf("failed to refresh token: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to refresh token, response: %s", string(body))
+		return fmt.Errorf("failed to parse response body: %w", err)
 	}
-	var tokenResp struct {
-		AccessToken string `json:"access_token"`
-		ExpiresIn   int    `json:"expires_in"`
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return fmt.Errorf("failed to parse response body: %w", err)
 	}
-	if err := json.Unmarshal(body, &tokenResp); err != nil {
-		return "", err
+	if trail := util.GetAuditTrailFromContext(ctx); trail != nil {
+		trail.AddWorksheet(sheetId, sheetName)
+		if snapshotId != "" {
+			trail.AddSnapshot(sheetName, snapshotId)
+		}
 	}
-	return tokenResp.AccessToken, nil
+	return nil
 }
 
-func (s *ZohoService) GetSheetData(ctx context.Context, sheetName string, response interface{}) error {
-	accessToken := util.GetZohoAccessTokenFromContext(ctx)
+// fetchRecords reads the worksheet from Zoho and returns its records array as fetched.
+func (s *ZohoService) fetchRecords(ctx context.Context, sheetName string) (json.RawMessage, error) {
+	accessToken, err := s.zohoTokenService.AccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
 	url1 := fmt.Sprintf("https://sheet.zoho.in/api/v2/%s", s.configuration.ZohoConfig.SheetId)
 	data := url.Values{}
 	data.Set("method", "worksheet.records.fetch")
@@ -84,9 +108,9 @@ func (s *ZohoService) GetSheetData(ctx context.Context, sheetName string, respon
 	data.Set("header_row", "1")
 
 	// Create a new HTTP request with POST method
-	req, err := http.NewRequest(http.MethodPost, url1, strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url1, strings.NewReader(data.Encode()))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set the required headers
@@ -94,21 +118,25 @@ func (s *ZohoService) GetSheetData(ctx context.Context, sheetName string, respon
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("error response from server: %s", string(body))
+		return nil, fmt.Errorf("error response from server: %s", string(body))
 	}
 	bytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
-	err = json.Unmarshal(bytes, &response)
+	var body sheetRecords
+	err = json.Unmarshal(bytes, &body)
 	if err != nil {
-		return fmt.Errorf("failed to parse response body: %w", err)
+		return nil, fmt.Errorf("failed to parse response body: %w", err)
 	}
-	return nil
+	if len(body.Records) == 0 || string(body.Records) == "null" {
+		body.Records = json.RawMessage("[]")
+	}
+	return body.Records, nil
 }
@@ -0,0 +1,9 @@
+package zoho_token
+
+import (
+	"context"
+)
+
+type Service interface {
+	AccessToken(ctx context.Context) (string, error)
+}
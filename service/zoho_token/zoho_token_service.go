@@ -0,0 +1,103 @@
+package zoho_token
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Out-Of-India-Theory/oit-go-commons/logging"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/configuration"
+	"go.uber.org/zap"
+)
+
+// tokens are refreshed this long before Zoho expires them, so a request never starts with a
+// token about to lapse
+const expiryMargin = time.Minute
+
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// ZohoTokenService obtains Zoho access tokens with the server's refresh token and caches
+// them until shortly before they expire. It is internal: callers never see the credentials
+// and API clients never supply Zoho tokens.
+type ZohoTokenService struct {
+	logger     *zap.Logger
+	config     configuration.ZohoConfig
+	httpClient *http.Client
+	mu         sync.Mutex
+	token      string
+	expiresAt  time.Time
+}
+
+func InitZohoTokenService(ctx context.Context,
+	configuration *configuration.Configuration,
+	httpClient *http.Client,
+) *ZohoTokenService {
+	return &ZohoTokenService{
+		logger:     logging.WithContext(ctx),
+		config:     configuration.ZohoConfig,
+		httpClient: httpClient,
+	}
+}
+
+func (s *ZohoTokenService) AccessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Now().Before(s.expiresAt) {
+		return s.token, nil
+	}
+	tokenResp, err := s.refresh(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = tokenResp.AccessToken
+	s.expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn)*time.Second - expiryMargin)
+	return s.token, nil
+}
+
+func (s *ZohoTokenService) refresh(ctx context.Context) (*TokenResponse, error) {
+	if s.config.RefreshToken == "" {
+		return nil, fmt.Errorf("refresh token not set")
+	}
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("refresh_token", s.config.RefreshToken)
+	data.Set("client_id", s.config.ClientId)
+	data.Set("client_secret", s.config.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.TokenUrl, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to refresh token, response: %s", string(body))
+	}
+	var tokenResp TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, err
+	}
+	if tokenResp.AccessToken == "" {
+		// Zoho answers 200 with an error body for a revoked refresh token
+		return nil, fmt.Errorf("failed to refresh token, response: %s", string(body))
+	}
+	return &tokenResp, nil
+}
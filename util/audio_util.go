@@ -0,0 +1,160 @@
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+	"github.com/hajimehoshi/go-mp3"
+)
+
+// PCM is decoded 16-bit audio with interleaved channels.
+type PCM struct {
+	SampleRate int
+	Channels   int
+	Samples    []int16
+}
+
+// DecodeAudio decodes a .wav or .mp3 file into 16-bit PCM. WAV samples of other bit depths
+// are scaled to 16 bits; MP3 always decodes to stereo.
+func DecodeAudio(data []byte, ext string) (*PCM, error) {
+	switch strings.ToLower(ext) {
+	case ".wav":
+		decoder := wav.NewDecoder(bytes.NewReader(data))
+		if !decoder.IsValidFile() {
+			return nil, fmt.Errorf("invalid WAV file")
+		}
+		buf, err := decoder.FullPCMBuffer()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding WAV: %w", err)
+		}
+		bitDepth := int(decoder.BitDepth)
+		samples := make([]int16, len(buf.Data))
+		for i, v := range buf.Data {
+			samples[i] = scaleTo16Bit(v, bitDepth)
+		}
+		return &PCM{SampleRate: buf.Format.SampleRate, Channels: buf.Format.NumChannels, Samples: samples}, nil
+	case ".mp3":
+		decoder, err := mp3.NewDecoder(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("error decoding MP3: %w", err)
+		}
+		raw, err := io.ReadAll(decoder)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding MP3: %w", err)
+		}
+		samples := make([]int16, len(raw)/2)
+		for i := range samples {
+			samples[i] = int16(binary.LittleEndian.Uint16(raw[2*i:]))
+		}
+		return &PCM{SampleRate: decoder.SampleRate(), Channels: 2, Samples: samples}, nil
+	default:
+		return nil, fmt.Errorf("unsupported audio type: %s", ext)
+	}
+}
+
+func scaleTo16Bit(v int, bitDepth int) int16 {
+	switch {
+	case bitDepth == 8:
+		// 8-bit WAV is unsigned
+		return int16((v - 128) << 8)
+	case bitDepth > 16:
+		return int16(v >> (bitDepth - 16))
+	default:
+		return int16(v)
+	}
+}
+
+func (p *PCM) Frames() int {
+	if p.Channels == 0 {
+		return 0
+	}
+	return len(p.Samples) / p.Channels
+}
+
+func (p *PCM) Duration() time.Duration {
+	if p.SampleRate == 0 {
+		return 0
+	}
+	return time.Duration(p.Frames()) * time.Second / time.Duration(p.SampleRate)
+}
+
+// Convert returns the audio resampled (linear interpolation) to the sample rate and mixed
+// to the channel count: mono is duplicated, anything to mono is averaged.
+func (p *PCM) Convert(sampleRate int, channels int) *PCM {
+	src := p
+	if p.Channels != channels {
+		src = p.remix(channels)
+	}
+	if src.SampleRate == sampleRate {
+		return src
+	}
+	srcFrames := src.Frames()
+	frames := int(math.Round(float64(srcFrames) * float64(sampleRate) / float64(src.SampleRate)))
+	samples := make([]int16, frames*channels)
+	step := float64(src.SampleRate) / float64(sampleRate)
+	for i := 0; i < frames; i++ {
+		pos := float64(i) * step
+		j := int(pos)
+		frac := pos - float64(j)
+		for c := 0; c < channels; c++ {
+			a := src.Samples[min(j, srcFrames-1)*channels+c]
+			b := src.Samples[min(j+1, srcFrames-1)*channels+c]
+			samples[i*channels+c] = int16(float64(a) + (float64(b)-float64(a))*frac)
+		}
+	}
+	return &PCM{SampleRate: sampleRate, Channels: channels, Samples: samples}
+}
+
+func (p *PCM) remix(channels int) *PCM {
+	frames := p.Frames()
+	samples := make([]int16, frames*channels)
+	for i := 0; i < frames; i++ {
+		frame := p.Samples[i*p.Channels : (i+1)*p.Channels]
+		if channels == 1 {
+			sum := 0
+			for _, v := range frame {
+				sum += int(v)
+			}
+			samples[i] = int16(sum / len(frame))
+			continue
+		}
+		for c := 0; c < channels; c++ {
+			samples[i*channels+c] = frame[c%p.Channels]
+		}
+	}
+	return &PCM{SampleRate: p.SampleRate, Channels: channels, Samples: samples}
+}
+
+// WavWriter streams 16-bit PCM into a WAV file; the header is finalized by Close.
+type WavWriter struct {
+	encoder  *wav.Encoder
+	channels int
+	format   *audio.Format
+}
+
+func NewWavWriter(w io.WriteSeeker, sampleRate int, channels int) *WavWriter {
+	return &WavWriter{
+		encoder:  wav.NewEncoder(w, sampleRate, 16, channels, 1),
+		channels: channels,
+		format:   &audio.Format{NumChannels: channels, SampleRate: sampleRate},
+	}
+}
+
+func (w *WavWriter) Write(samples []int16) error {
+	data := make([]int, len(samples))
+	for i, v := range samples {
+		data[i] = int(v)
+	}
+	return w.encoder.Write(&audio.IntBuffer{Format: w.format, Data: data, SourceBitDepth: 16})
+}
+
+func (w *WavWriter) Close() error {
+	return w.encoder.Close()
+}
@@ -0,0 +1,171 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
+
+// maxAuditWarnings caps the warnings kept in an audit record; all of them are counted
+const maxAuditWarnings = 100
+
+// maxAuditChanges caps the document changes kept in an audit record, so a full ingestion
+// stays well below the document size limit; all of them are counted
+const maxAuditChanges = 1000
+
+// AuditTrail collects what a request read and wrote while it runs. It is carried in the
+// request context so the zoho service, the repositories and the controllers can add to it
+// without knowing about the audit log.
+type AuditTrail struct {
+	mu      sync.Mutex
+	source  entity.AuditSource
+	counts  entity.AuditCounts
+	changes []entity.DocumentChange // capped at maxAuditChanges
+	skipped []string                // listed in the report, only counted in the audit record
+	// warnings are capped at maxAuditWarnings, warningCounts counts every warning
+	warnings      []entity.IngestionWarning
+	warningCounts map[string]int
+	changedAssets []string
+	invalidations []string
+}
+
+func NewAuditTrail() *AuditTrail {
+	return &AuditTrail{}
+}
+
+func (t *AuditTrail) AddWorksheet(sheetId string, worksheet string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.source.SheetId = sheetId
+	if !slices.Contains(t.source.Worksheets, worksheet) {
+		t.source.Worksheets = append(t.source.Worksheets, worksheet)
+	}
+}
+
+// AddSnapshot records the snapshot a worksheet was read from or saved to.
+func (t *AuditTrail) AddSnapshot(worksheet string, snapshotId string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.source.Snapshots == nil {
+		t.source.Snapshots = make(map[string]string)
+	}
+	t.source.Snapshots[worksheet] = snapshotId
+}
+
+func (t *AuditTrail) AddFile(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.source.Files = append(t.source.Files, name)
+}
+
+func (t *AuditTrail) AddChange(change entity.DocumentChange) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch change.Action {
+	case entity.ChangeInserted:
+		t.counts.Inserted++
+	case entity.ChangeUpdated:
+		t.counts.Updated++
+	case entity.ChangeSkipped:
+		t.counts.Skipped++
+		t.skipped = append(t.skipped, change.Collection+"/"+change.Id)
+		return
+	default:
+		t.counts.Unchanged++
+		// unchanged documents are only counted
+		return
+	}
+	if len(t.changes) < maxAuditChanges {
+		t.changes = append(t.changes, change)
+	}
+}
+
+func (t *AuditTrail) AddWarning(warning entity.IngestionWarning) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.counts.Warnings++
+	if t.warningCounts == nil {
+		t.warningCounts = make(map[string]int)
+	}
+	t.warningCounts[warning.ContentType]++
+	if len(t.warnings) < maxAuditWarnings {
+		t.warnings = append(t.warnings, warning)
+	}
+}
+
+// AddChangedAsset records the storage key of an asset that was overwritten, so its CDN copy
+// can be invalidated.
+func (t *AuditTrail) AddChangedAsset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !slices.Contains(t.changedAssets, key) {
+		t.changedAssets = append(t.changedAssets, key)
+	}
+}
+
+func (t *AuditTrail) ChangedAssets() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.Clone(t.changedAssets)
+}
+
+func (t *AuditTrail) AddInvalidation(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.invalidations = append(t.invalidations, id)
+}
+
+// Report summarizes the trail for an ingestion response: the counts, the skipped rows and the
+// overwritten assets.
+func (t *AuditTrail) Report() entity.IngestionReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return entity.IngestionReport{
+		Counts:        t.counts,
+		Skipped:       append([]string{}, t.skipped...),
+		ChangedAssets: append([]string{}, t.changedAssets...),
+	}
+}
+
+// Fill copies the collected source, counts, changes and assets into the audit record.
+func (t *AuditTrail) Fill(audit *entity.IngestionAudit) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	audit.Source = t.source
+	audit.Counts = t.counts
+	audit.Changes = slices.Clone(t.changes)
+	audit.Warnings = slices.Clone(t.warnings)
+	audit.WarningCounts = maps.Clone(t.warningCounts)
+	audit.ChangedAssets = slices.Clone(t.changedAssets)
+	audit.Invalidations = slices.Clone(t.invalidations)
+}
+
+// RecordChange adds a document write to the audit trail of the context, if there is one.
+func RecordChange(ctx context.Context, collection string, id string, action string, fields []string) {
+	if trail := GetAuditTrailFromContext(ctx); trail != nil {
+		trail.AddChange(entity.DocumentChange{Collection: collection, Id: id, Action: action, Fields: fields})
+	}
+}
+
+// RecordChangedAsset adds an overwritten asset to the audit trail of the context, if there is
+// one.
+func RecordChangedAsset(ctx context.Context, key string) {
+	if trail := GetAuditTrailFromContext(ctx); trail != nil {
+		trail.AddChangedAsset(key)
+	}
+}
+
+// RecordWarning logs a validation warning and adds it to the audit trail of the context, if
+// there is one.
+func RecordWarning(ctx context.Context, contentType string, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	log.Printf("Warning: %s\n", message)
+	if trail := GetAuditTrailFromContext(ctx); trail != nil {
+		trail.AddWarning(entity.IngestionWarning{ContentType: contentType, Message: message})
+	}
+}
@@ -1,18 +1,66 @@
 package util
 
-import "context"
+import (
+	"context"
 
-const accessTokenKey = "access-token"
+	"github.com/Out-Of-India-Theory/prarthana-ingestion-script/entity"
+)
 
-func GetZohoAccessTokenFromContext(ctx context.Context) string {
-	lang, ok := ctx.Value(accessTokenKey).(string)
+const (
+	principalKey  = "principal"
+	auditTrailKey = "auditTrail"
+	snapshotsKey  = "sheetSnapshots"
+	runIdKey      = "runId"
+)
+
+func GetPrincipalFromContext(ctx context.Context) *entity.Principal {
+	principal, ok := ctx.Value(principalKey).(*entity.Principal)
+	if ok {
+		return principal
+	}
+	return nil
+}
+
+func SetPrincipalInContext(ctx context.Context, principal *entity.Principal) context.Context {
+	ctx = context.WithValue(ctx, principalKey, principal)
+	return ctx
+}
+
+func GetAuditTrailFromContext(ctx context.Context) *AuditTrail {
+	trail, ok := ctx.Value(auditTrailKey).(*AuditTrail)
+	if ok {
+		return trail
+	}
+	return nil
+}
+
+func SetAuditTrailInContext(ctx context.Context, trail *AuditTrail) context.Context {
+	ctx = context.WithValue(ctx, auditTrailKey, trail)
+	return ctx
+}
+
+// GetSheetSnapshotsFromContext returns the snapshot ids, by worksheet, that sheet reads of
+// the request must use instead of live Zoho data.
+func GetSheetSnapshotsFromContext(ctx context.Context) map[string]string {
+	snapshots, ok := ctx.Value(snapshotsKey).(map[string]string)
 	if ok {
-		return lang
+		return snapshots
 	}
-	return ""
+	return nil
+}
+
+func SetSheetSnapshotsInContext(ctx context.Context, snapshots map[string]string) context.Context {
+	ctx = context.WithValue(ctx, snapshotsKey, snapshots)
+	return ctx
+}
+
+// GetRunIdFromContext returns the id of the ingestion run, the id of its audit record.
+func GetRunIdFromContext(ctx context.Context) string {
+	runId, _ := ctx.Value(runIdKey).(string)
+	return runId
 }
 
-func SetZohoAccessTokenInContext(ctx context.Context, accessToken string) context.Context {
-	ctx = context.WithValue(ctx, accessTokenKey, accessToken)
+func SetRunIdInContext(ctx context.Context, runId string) context.Context {
+	ctx = context.WithValue(ctx, runIdKey, runId)
 	return ctx
 }
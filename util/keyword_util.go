@@ -0,0 +1,88 @@
+package util
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// KeywordBuilder collects normalized, de-duplicated search keywords in the order they
+// were added. Indic-script values also contribute their Latin transliterations.
+type KeywordBuilder struct {
+	seen     map[string]bool
+	keywords []string
+}
+
+func NewKeywordBuilder() *KeywordBuilder {
+	return &KeywordBuilder{seen: make(map[string]bool)}
+}
+
+func (b *KeywordBuilder) Add(values ...string) *KeywordBuilder {
+	for _, value := range values {
+		keyword := NormalizeKeyword(value)
+		b.add(keyword)
+		if IsIndicScript(keyword) {
+			b.add(NormalizeKeyword(TransliterateToLatin(keyword, false)))
+			b.add(NormalizeKeyword(TransliterateToLatin(keyword, true)))
+		}
+	}
+	return b
+}
+
+func (b *KeywordBuilder) AddMap(values map[string]string) *KeywordBuilder {
+	for _, lang := range sortedKeys(values) {
+		b.Add(values[lang])
+	}
+	return b
+}
+
+func (b *KeywordBuilder) AddListMap(values map[string][]string) *KeywordBuilder {
+	for _, lang := range sortedKeys(values) {
+		b.Add(values[lang]...)
+	}
+	return b
+}
+
+func (b *KeywordBuilder) Keywords() []string {
+	if b.keywords == nil {
+		return []string{}
+	}
+	return b.keywords
+}
+
+func (b *KeywordBuilder) add(keyword string) {
+	if keyword == "" || b.seen[keyword] {
+		return
+	}
+	b.seen[keyword] = true
+	b.keywords = append(b.keywords, keyword)
+}
+
+// NormalizeKeyword lowercases a value, turns punctuation and underscores into spaces and
+// collapses whitespace, keeping letters, combining marks (needed by Indic scripts) and digits.
+func NormalizeKeyword(value string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// sortedKeys orders languages with "default" first so the canonical value leads the keyword list.
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i] == "default" || keys[j] == "default" {
+			return keys[i] == "default" && keys[j] != "default"
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
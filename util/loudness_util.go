@@ -0,0 +1,201 @@
+package util
+
+import (
+	"math"
+)
+
+const (
+	loudnessBlockSeconds = 0.4
+	loudnessBlockOverlap = 0.75
+	absoluteGateLufs     = -70.0
+	relativeGateLu       = -10.0
+	silenceThresholdDbfs = -50.0
+	clippingLevel        = math.MaxInt16 - 1
+	// reported instead of -Inf, which JSON cannot carry
+	silentPeakDbfs = -96.0
+)
+
+// LoudnessStats summarizes the level of a recording. IntegratedLufs follows ITU-R BS.1770
+// (K-weighting, 400ms blocks, absolute and relative gating). Silence reports the -70 LUFS
+// absolute gate and a -96 dBFS peak.
+type LoudnessStats struct {
+	IntegratedLufs    float64
+	PeakDbfs          float64
+	LeadingSilenceMs  int
+	TrailingSilenceMs int
+	ClippedSamples    int
+}
+
+func AnalyzeLoudness(pcm *PCM) LoudnessStats {
+	stats := LoudnessStats{IntegratedLufs: absoluteGateLufs, PeakDbfs: silentPeakDbfs}
+	frames := pcm.Frames()
+	if frames == 0 {
+		return stats
+	}
+
+	peak := 0
+	for _, v := range pcm.Samples {
+		a := abs(int(v))
+		if a > peak {
+			peak = a
+		}
+		if a >= clippingLevel {
+			stats.ClippedSamples++
+		}
+	}
+	if peak > 0 {
+		stats.PeakDbfs = 20 * math.Log10(float64(peak)/math.MaxInt16)
+	}
+
+	threshold := int(math.MaxInt16 * math.Pow(10, silenceThresholdDbfs/20))
+	first, last := -1, -1
+	for i := 0; i < frames; i++ {
+		if frameAbove(pcm, i, threshold) {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		stats.LeadingSilenceMs = framesToMs(frames, pcm.SampleRate)
+		stats.TrailingSilenceMs = stats.LeadingSilenceMs
+		return stats
+	}
+	stats.LeadingSilenceMs = framesToMs(first, pcm.SampleRate)
+	stats.TrailingSilenceMs = framesToMs(frames-1-last, pcm.SampleRate)
+	stats.IntegratedLufs = integratedLoudness(pcm)
+	return stats
+}
+
+func frameAbove(pcm *PCM, frame int, threshold int) bool {
+	for c := 0; c < pcm.Channels; c++ {
+		if abs(int(pcm.Samples[frame*pcm.Channels+c])) > threshold {
+			return true
+		}
+	}
+	return false
+}
+
+func integratedLoudness(pcm *PCM) float64 {
+	// blocks overlap by whole hops, so the mean square of a block is the sum of its hops'
+	// sums of squares; those are accumulated per channel while filtering, summed over
+	// channels (all weighted 1.0)
+	hopsPerBlock := int(math.Round(1 / (1 - loudnessBlockOverlap)))
+	hopSize := int(loudnessBlockSeconds * (1 - loudnessBlockOverlap) * float64(pcm.SampleRate))
+	if hopSize == 0 {
+		return absoluteGateLufs
+	}
+	blockSize := hopSize * hopsPerBlock
+	hopSums := make([]float64, pcm.Frames()/hopSize)
+	for c := 0; c < pcm.Channels; c++ {
+		kWeight(pcm, c, hopSize, hopSums)
+	}
+	var powers []float64
+	for start := 0; start+hopsPerBlock <= len(hopSums); start++ {
+		sum := 0.0
+		for _, hopSum := range hopSums[start : start+hopsPerBlock] {
+			sum += hopSum
+		}
+		powers = append(powers, sum/float64(blockSize))
+	}
+
+	gated := gate(powers, absoluteGateLufs)
+	if len(gated) == 0 {
+		return absoluteGateLufs
+	}
+	gated = gate(gated, lufs(mean(gated))+relativeGateLu)
+	if len(gated) == 0 {
+		return absoluteGateLufs
+	}
+	return lufs(mean(gated))
+}
+
+func gate(powers []float64, thresholdLufs float64) []float64 {
+	var kept []float64
+	for _, power := range powers {
+		if lufs(power) > thresholdLufs {
+			kept = append(kept, power)
+		}
+	}
+	return kept
+}
+
+func lufs(power float64) float64 {
+	return -0.691 + 10*math.Log10(power)
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// kWeight applies the BS.1770 pre-filter (a high shelf, then a high pass) to one channel,
+// with the biquads designed for the recording's sample rate, and adds the squares of the
+// filtered samples of every complete hop to hopSums.
+func kWeight(pcm *PCM, channel int, hopSize int, hopSums []float64) {
+	rate := float64(pcm.SampleRate)
+	shelf := highShelf(rate, 1681.974450955533, 3.999843853973347, 0.7071752369554196)
+	highPass := highPass(rate, 38.13547087602444, 0.5003270373238773)
+	frames := len(hopSums) * hopSize
+	for i := 0; i < frames; i++ {
+		x := float64(pcm.Samples[i*pcm.Channels+channel]) / math.MaxInt16
+		y := highPass.process(shelf.process(x))
+		hopSums[i/hopSize] += y * y
+	}
+}
+
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+	f.x2, f.x1 = f.x1, x
+	f.y2, f.y1 = f.y1, y
+	return y
+}
+
+func highShelf(rate, fc, gainDb, q float64) *biquad {
+	k := math.Tan(math.Pi * fc / rate)
+	vh := math.Pow(10, gainDb/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	return &biquad{
+		b0: (vh + vb*k/q + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/q + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+}
+
+func highPass(rate, fc, q float64) *biquad {
+	k := math.Tan(math.Pi * fc / rate)
+	a0 := 1 + k/q + k*k
+	return &biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+}
+
+func framesToMs(frames int, sampleRate int) int {
+	if sampleRate == 0 {
+		return 0
+	}
+	return int(int64(frames) * 1000 / int64(sampleRate))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"math"
+)
+
+const pauseWindowMs = 50
+
+// Pause is a stretch of near silence inside a recording.
+type Pause struct {
+	StartMs int
+	EndMs   int
+}
+
+func (p Pause) DurationMs() int {
+	return p.EndMs - p.StartMs
+}
+
+func (p Pause) MidpointMs() int {
+	return (p.StartMs + p.EndMs) / 2
+}
+
+// DetectPauses returns the pauses of at least minPauseMs whose 50ms windows stay below the
+// threshold, excluding the silence before the first and after the last sound.
+func DetectPauses(pcm *PCM, thresholdDbfs float64, minPauseMs int) []Pause {
+	window := pcm.SampleRate * pauseWindowMs / 1000
+	if window == 0 || pcm.Channels == 0 {
+		return nil
+	}
+	threshold := math.Pow(10, thresholdDbfs/20)
+	windows := pcm.Frames() / window
+	silent := make([]bool, windows)
+	for w := range silent {
+		samples := pcm.Samples[w*window*pcm.Channels : (w+1)*window*pcm.Channels]
+		sum := 0.0
+		for _, v := range samples {
+			x := float64(v) / math.MaxInt16
+			sum += x * x
+		}
+		silent[w] = math.Sqrt(sum/float64(len(samples))) < threshold
+	}
+
+	var pauses []Pause
+	start := -1
+	heardSound := false
+	for w, isSilent := range silent {
+		switch {
+		case isSilent && start < 0:
+			start = w
+		case !isSilent:
+			if start >= 0 && heardSound && (w-start)*pauseWindowMs >= minPauseMs {
+				pauses = append(pauses, Pause{StartMs: start * pauseWindowMs, EndMs: w * pauseWindowMs})
+			}
+			start = -1
+			heardSound = true
+		}
+	}
+	return pauses
+}
@@ -0,0 +1,27 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// SourceHash fingerprints a sheet row and anything else a document is built from, so an
+// incremental ingestion can tell whether the document would change. The row's position in
+// the sheet is left out; JSON encoding sorts map keys, which keeps the hash stable.
+func SourceHash(record map[string]interface{}, derived ...interface{}) string {
+	row := make(map[string]interface{}, len(record))
+	for column, value := range record {
+		if column != "row_index" {
+			row[column] = value
+		}
+	}
+	hash := sha256.New()
+	encoder := json.NewEncoder(hash)
+	// encoding sheet values and built entities cannot fail
+	_ = encoder.Encode(row)
+	for _, part := range derived {
+		_ = encoder.Encode(part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,131 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The Brahmic script blocks from Bengali (U+0980) to Malayalam (U+0D7F) share the
+// Devanagari layout, so every supported script is transliterated by shifting it into
+// the Devanagari block first.
+const (
+	devanagariBlock = 0x0900
+	indicBlockStart = 0x0900
+	indicBlockEnd   = 0x0D7F
+	virama          = 0x094D
+	nukta           = 0x093C
+)
+
+var devanagariVowels = map[rune]string{
+	0x0905: "a", 0x0906: "aa", 0x0907: "i", 0x0908: "ee", 0x0909: "u", 0x090A: "oo",
+	0x090B: "ri", 0x090C: "lri", 0x090D: "e", 0x090E: "e", 0x090F: "e", 0x0910: "ai",
+	0x0911: "o", 0x0912: "o", 0x0913: "o", 0x0914: "au", 0x0960: "ri",
+}
+
+var devanagariConsonants = map[rune]string{
+	0x0915: "k", 0x0916: "kh", 0x0917: "g", 0x0918: "gh", 0x0919: "n",
+	0x091A: "ch", 0x091B: "chh", 0x091C: "j", 0x091D: "jh", 0x091E: "n",
+	0x091F: "t", 0x0920: "th", 0x0921: "d", 0x0922: "dh", 0x0923: "n",
+	0x0924: "t", 0x0925: "th", 0x0926: "d", 0x0927: "dh", 0x0928: "n", 0x0929: "n",
+	0x092A: "p", 0x092B: "ph", 0x092C: "b", 0x092D: "bh", 0x092E: "m",
+	0x092F: "y", 0x0930: "r", 0x0931: "r", 0x0932: "l", 0x0933: "l", 0x0934: "zh",
+	0x0935: "v", 0x0936: "sh", 0x0937: "sh", 0x0938: "s", 0x0939: "h",
+	0x0958: "q", 0x0959: "kh", 0x095A: "g", 0x095B: "z", 0x095C: "r", 0x095D: "rh",
+	0x095E: "f", 0x095F: "y",
+}
+
+var devanagariMatras = map[rune]string{
+	0x093E: "aa", 0x093F: "i", 0x0940: "ee", 0x0941: "u", 0x0942: "oo", 0x0943: "ri",
+	0x0944: "ri", 0x0945: "e", 0x0946: "e", 0x0947: "e", 0x0948: "ai", 0x0949: "o",
+	0x094A: "o", 0x094B: "o", 0x094C: "au",
+}
+
+var devanagariSigns = map[rune]string{
+	0x0901: "n", 0x0902: "n", 0x0903: "h", 0x093D: "", 0x0950: "om",
+}
+
+// IsIndicScript reports whether text contains any character from a supported Indic script.
+func IsIndicScript(text string) bool {
+	for _, r := range text {
+		if r >= indicBlockStart && r <= indicBlockEnd {
+			return true
+		}
+	}
+	return false
+}
+
+// TransliterateToLatin converts Indic-script text into a plain ASCII spelling, e.g.
+// "गणेश" becomes "ganesha". With dropFinalSchwa the inherent vowel at the end of each
+// word is omitted, giving the colloquial "ganesh". Latin text is passed through lowercased.
+func TransliterateToLatin(text string, dropFinalSchwa bool) string {
+	runes := []rune(text)
+	for i, r := range runes {
+		runes[i] = toDevanagari(r)
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if consonant, ok := devanagariConsonants[r]; ok {
+			sb.WriteString(consonant)
+			next := i + 1
+			if next < len(runes) && runes[next] == nukta {
+				next++
+			}
+			switch {
+			case next < len(runes) && runes[next] == virama:
+				i = next
+			case next < len(runes) && devanagariMatras[runes[next]] != "":
+				sb.WriteString(devanagariMatras[runes[next]])
+				i = next
+			case dropFinalSchwa && (next >= len(runes) || !isDevanagariLetter(runes[next])):
+				i = next - 1
+			default:
+				sb.WriteString("a")
+				i = next - 1
+			}
+			continue
+		}
+		if vowel, ok := devanagariVowels[r]; ok {
+			sb.WriteString(vowel)
+			continue
+		}
+		if sign, ok := devanagariSigns[r]; ok {
+			sb.WriteString(sign)
+			continue
+		}
+		if r >= 0x0966 && r <= 0x096F {
+			sb.WriteRune('0' + (r - 0x0966))
+			continue
+		}
+		if r >= indicBlockStart && r <= indicBlockEnd {
+			// dandas and marks without a Latin equivalent separate words
+			if r == 0x0964 || r == 0x0965 {
+				sb.WriteRune(' ')
+			}
+			continue
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+func toDevanagari(r rune) rune {
+	if r > 0x097F && r <= indicBlockEnd {
+		return r - (r &^ 0x7F) + devanagariBlock
+	}
+	return r
+}
+
+func isDevanagariLetter(r rune) bool {
+	if _, ok := devanagariConsonants[r]; ok {
+		return true
+	}
+	if _, ok := devanagariMatras[r]; ok {
+		return true
+	}
+	if _, ok := devanagariSigns[r]; ok {
+		return true
+	}
+	return r == virama || r == nukta
+}
@@ -1,8 +1,6 @@
 package util
 
 import (
-	"log"
-	"net/http"
 	"regexp"
 	"strings"
 )
@@ -47,16 +45,6 @@ func GetDaysFromTitle(title string) []int {
 	return nil
 }
 
-func UrlExists(url string) bool {
-	resp, err := http.Head(url)
-	if err != nil {
-		log.Printf("Error checking URL: %s, %v", url, err)
-		return false
-	}
-	defer resp.Body.Close()
-	return resp.StatusCode == http.StatusOK
-}
-
 func SanitizeString(input string) string {
 	// Remove square brackets and their contents
 	reSquareBrackets := regexp.MustCompile(`\[.*?\]`)
@@ -0,0 +1,87 @@
+package util
+
+import (
+	"math"
+)
+
+const waveformBlockFrames = 64
+
+// Waveform is peak data in the audiowaveform JSON format: Data holds a min and a max per
+// pixel, scaled to 8 bits and mixed across channels.
+type Waveform struct {
+	Version         int    `json:"version"`
+	Channels        int    `json:"channels"`
+	SampleRate      int    `json:"sample_rate"`
+	SamplesPerPixel int    `json:"samples_per_pixel"`
+	Bits            int    `json:"bits"`
+	Length          int    `json:"length"`
+	Data            []int8 `json:"data"`
+}
+
+// WaveformBuilder accumulates min/max peaks of fixed size blocks as audio is streamed
+// through it, so the length need not be known until Build.
+type WaveformBuilder struct {
+	sampleRate int
+	channels   int
+	mins       []int16
+	maxs       []int16
+	blockMin   int16
+	blockMax   int16
+	inBlock    int
+}
+
+func NewWaveformBuilder(sampleRate int, channels int) *WaveformBuilder {
+	return &WaveformBuilder{sampleRate: sampleRate, channels: channels}
+}
+
+func (b *WaveformBuilder) Add(samples []int16) {
+	for i := 0; i+b.channels <= len(samples); i += b.channels {
+		for _, v := range samples[i : i+b.channels] {
+			if b.inBlock == 0 || v < b.blockMin {
+				b.blockMin = v
+			}
+			if b.inBlock == 0 || v > b.blockMax {
+				b.blockMax = v
+			}
+		}
+		b.inBlock++
+		if b.inBlock == waveformBlockFrames {
+			b.endBlock()
+		}
+	}
+}
+
+func (b *WaveformBuilder) endBlock() {
+	b.mins = append(b.mins, b.blockMin)
+	b.maxs = append(b.maxs, b.blockMax)
+	b.inBlock = 0
+}
+
+// Build merges the blocks into at most points pixels.
+func (b *WaveformBuilder) Build(points int) Waveform {
+	if b.inBlock > 0 {
+		b.endBlock()
+	}
+	perPixel := 1
+	if points > 0 && len(b.mins) > points {
+		perPixel = int(math.Ceil(float64(len(b.mins)) / float64(points)))
+	}
+	waveform := Waveform{
+		Version:         2,
+		Channels:        1,
+		SampleRate:      b.sampleRate,
+		SamplesPerPixel: perPixel * waveformBlockFrames,
+		Bits:            8,
+	}
+	for start := 0; start < len(b.mins); start += perPixel {
+		end := min(start+perPixel, len(b.mins))
+		lo, hi := b.mins[start], b.maxs[start]
+		for i := start + 1; i < end; i++ {
+			lo = min(lo, b.mins[i])
+			hi = max(hi, b.maxs[i])
+		}
+		waveform.Data = append(waveform.Data, int8(lo>>8), int8(hi>>8))
+	}
+	waveform.Length = len(waveform.Data) / 2
+	return waveform
+}